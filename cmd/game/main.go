@@ -1,16 +1,30 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"time"
 
 	"ecs/internal/game"
+	"ecs/internal/game/dungeon"
 	"ecs/internal/ui"
 )
 
 func main() {
+	continueGame := flag.Bool("continue", false, "continue the last saved game")
+	contentDir := flag.String("content", "content", "directory to load creatures and items from")
+	seed := flag.Uint64("seed", 0, "seed for the level and every roll, random when 0")
+	algorithm := flag.String("dungeon", "", "how to generate the level: rooms, bsp or caves")
+	flag.Parse()
+
+	if *algorithm != "" && !slices.Contains(dungeon.Algorithms, dungeon.Algorithm(*algorithm)) {
+		fmt.Printf("Unknown dungeon %q, expected rooms, bsp or caves\n", *algorithm)
+		os.Exit(1)
+	}
+
 	debug := false
 	var logger *log.Logger
 	if debug {
@@ -24,6 +38,30 @@ func main() {
 		logger = log.New(os.Stdout, "", log.LstdFlags)
 	}
 
-	game := game.NewGame(logger)
-	ui.RunGame(game, logger)
+	var g *game.Game
+	if *continueGame {
+		loaded, err := game.LoadFromFile(game.SavePath, logger, *contentDir)
+		if err != nil {
+			fmt.Printf("Error loading saved game: %v\n", err)
+			os.Exit(1)
+		}
+		g = loaded
+	} else {
+		created, err := game.NewGame(logger, *contentDir)
+		if err != nil {
+			fmt.Printf("Error creating game: %v\n", err)
+			os.Exit(1)
+		}
+		g = created
+		if *seed != 0 {
+			g.SetSeed(*seed)
+		}
+		g.SetDungeonAlgorithm(dungeon.Algorithm(*algorithm))
+		if err := g.Initialize(); err != nil {
+			fmt.Printf("Error starting game: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ui.RunGame(g, logger)
 }
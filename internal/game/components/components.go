@@ -2,25 +2,30 @@ package components
 
 import "ecs/pkg/ecs"
 
-// Component type constants
-const (
-	Position         ecs.ComponentType = "position"
-	Health           ecs.ComponentType = "health"
-	Strength         ecs.ComponentType = "strength"
-	Sprite           ecs.ComponentType = "sprite"
-	Inventory        ecs.ComponentType = "inventory"
-	Item             ecs.ComponentType = "item"
-	Weapon           ecs.ComponentType = "weapon"
-	Armor            ecs.ComponentType = "armor"
-	Equippable       ecs.ComponentType = "equippable"
-	Usable           ecs.ComponentType = "usable"
-	PlayerControlled ecs.ComponentType = "player_controlled"
-	MoveIntent       ecs.ComponentType = "move_intent"
-	AttackIntent     ecs.ComponentType = "attack_intent"
-	PickupIntent     ecs.ComponentType = "pickup_intent"
-	UseItemIntent    ecs.ComponentType = "use_item_intent"
-	EquipIntent      ecs.ComponentType = "equip_intent"
-	UnequipIntent    ecs.ComponentType = "unequip_intent"
+// Component type keys, derived from the component structs themselves
+var (
+	Position         = ecs.TypeOf[PositionComponent]()
+	Health           = ecs.TypeOf[HealthComponent]()
+	Strength         = ecs.TypeOf[StrengthComponent]()
+	Sprite           = ecs.TypeOf[SpriteComponent]()
+	Speed            = ecs.TypeOf[SpeedComponent]()
+	Inventory        = ecs.TypeOf[InventoryComponent]()
+	Item             = ecs.TypeOf[ItemComponent]()
+	Weapon           = ecs.TypeOf[WeaponComponent]()
+	Armor            = ecs.TypeOf[ArmorComponent]()
+	Equippable       = ecs.TypeOf[EquippableComponent]()
+	Usable           = ecs.TypeOf[UsableComponent]()
+	DropTable        = ecs.TypeOf[DropTableComponent]()
+	FieldOfView      = ecs.TypeOf[FieldOfViewComponent]()
+	PlayerControlled = ecs.TypeOf[PlayerControlledComponent]()
+	Blocker          = ecs.TypeOf[BlockerComponent]()
+	Hostile          = ecs.TypeOf[HostileComponent]()
+	MoveIntent       = ecs.TypeOf[MoveIntentComponent]()
+	AttackIntent     = ecs.TypeOf[AttackIntentComponent]()
+	PickupIntent     = ecs.TypeOf[PickupIntentComponent]()
+	UseItemIntent    = ecs.TypeOf[UseItemIntentComponent]()
+	EquipIntent      = ecs.TypeOf[EquipIntentComponent]()
+	UnequipIntent    = ecs.TypeOf[UnequipIntentComponent]()
 )
 
 type EquipmentSlot string
@@ -64,11 +69,31 @@ type SpriteComponent struct {
 	Char rune
 }
 
+// NormalSpeed is the speed of an entity that acts once for every action of a normal entity
+const NormalSpeed = 100
+
+// SpeedComponent stores how quickly an entity acts
+// It gains this much energy every tick of the turn manager, and acts once it has enough
+type SpeedComponent struct {
+	ComponentType
+	Speed int
+}
+
 // PlayerControlledComponent marks an entity as player-controlled
 type PlayerControlledComponent struct {
 	ComponentType
 }
 
+// BlockerComponent marks an entity that nothing else can move onto the tile of
+type BlockerComponent struct {
+	ComponentType
+}
+
+// HostileComponent marks a monster, which fights anything that isn't hostile (ie. the player)
+type HostileComponent struct {
+	ComponentType
+}
+
 type InventoryComponent struct {
 	ComponentType
 	Items       []ecs.Entity
@@ -104,6 +129,32 @@ type UsableComponent struct {
 	Power  int
 }
 
+// DropTableComponent lists the items a creature may drop when it is defeated
+type DropTableComponent struct {
+	ComponentType
+	Drops []Drop
+}
+
+// Drop is a prefab that is dropped with the given chance (0 to 1)
+type Drop struct {
+	Prefab string
+	Chance float64
+}
+
+// FieldOfViewComponent stores which tiles of the level an entity can currently see
+// Visible is in the same order as the tile map's tiles, and is kept up to date by the FOV system
+type FieldOfViewComponent struct {
+	ComponentType
+	Radius  int
+	Visible []bool
+}
+
+// CanSee reports whether the tile at x, y on a map of the given width is in view
+func (f *FieldOfViewComponent) CanSee(x, y, width int) bool {
+	index := y*width + x
+	return x >= 0 && x < width && index >= 0 && index < len(f.Visible) && f.Visible[index]
+}
+
 // MoveIntentComponent represents intention to move
 type MoveIntentComponent struct {
 	ComponentType
@@ -145,13 +196,18 @@ var ComponentTypes = []ecs.ComponentType{
 	Health,
 	Strength,
 	Sprite,
+	Speed,
 	Inventory,
 	Item,
 	Weapon,
 	Armor,
 	Equippable,
 	Usable,
+	DropTable,
+	FieldOfView,
 	PlayerControlled,
+	Blocker,
+	Hostile,
 	MoveIntent,
 	AttackIntent,
 	PickupIntent,
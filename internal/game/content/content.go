@@ -0,0 +1,358 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Content holds the creatures and items defined in the content files
+// Each file is a JSON object with "creatures" and / or "items" lists
+type Content struct {
+	Creatures []Creature
+	Items     []Item
+
+	// positions remembers where each definition came from, for error messages
+	positions map[string]*entry
+}
+
+// Creature defines a monster that can be spawned by name
+// Fields left out are inherited from the creature it extends, through its prefab
+type Creature struct {
+	Name     string `json:"name"`
+	Extends  string `json:"extends"`
+	Sprite   string `json:"sprite"`
+	HP       *int   `json:"hp"`
+	Strength *int   `json:"strength"`
+	Depth    *int   `json:"depth"` // Shallowest level the creature is found on, 1 when left out
+	Speed    *int   `json:"speed"` // How quickly the creature acts, 100 (normal) when left out
+	Drops    []Drop `json:"drops"`
+}
+
+// Drop is an item a creature drops when defeated, with a chance between 0 and 1
+type Drop struct {
+	Item   string  `json:"item"`
+	Chance float64 `json:"chance"`
+}
+
+// Item defines an item that can be spawned by name
+// Fields left out are inherited from the item it extends, through its prefab. DisplayName,
+// Weight and Value make up one component, as do Effect and Power, so they are inherited together
+type Item struct {
+	Name        string   `json:"name"`
+	Extends     string   `json:"extends"`
+	DisplayName string   `json:"displayName"`
+	Sprite      string   `json:"sprite"`
+	Weight      *int     `json:"weight"`
+	Value       *int     `json:"value"`
+	Effect      string   `json:"effect"`
+	Power       *int     `json:"power"`
+	Damage      *int     `json:"damage"`
+	Defense     *int     `json:"defense"`
+	Slots       []string `json:"slots"`
+}
+
+// CreatureNames returns the name of every creature found at the depth or shallower,
+// in the order they were loaded
+func (c *Content) CreatureNames(depth int) []string {
+	names := []string{}
+	for _, creature := range c.Creatures {
+		if c.depth(creature) <= depth {
+			names = append(names, creature.Name)
+		}
+	}
+	return names
+}
+
+// depth returns the shallowest level the creature is found on, which it inherits from the
+// creature it extends when left out
+func (c *Content) depth(creature Creature) int {
+	// Content is validated when loaded, so the creatures it extends exist and don't repeat
+	for creature.Depth == nil && creature.Extends != "" {
+		creature, _ = c.creature(creature.Extends)
+	}
+	return valueOr(creature.Depth, 1)
+}
+
+// ItemNames returns the name of every item, in the order they were loaded
+func (c *Content) ItemNames() []string {
+	names := make([]string, len(c.Items))
+	for i, item := range c.Items {
+		names[i] = item.Name
+	}
+	return names
+}
+
+// Error is a problem with a content file, pointing at the line and column it was found at
+type Error struct {
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// entry is one creature or item definition, and where it was found
+type entry struct {
+	file   string
+	data   []byte // The whole file
+	offset int    // Offset of the definition in the file
+	raw    json.RawMessage
+}
+
+// errorAt returns an error pointing at the offset within the definition
+func (e *entry) errorAt(offset int, format string, args ...any) *Error {
+	line, column := lineColumn(e.data, e.offset+offset)
+	return &Error{File: e.file, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// errorAtField returns an error pointing at the field of the definition,
+// or the definition itself if it doesn't have the field
+func (e *entry) errorAtField(field string, format string, args ...any) *Error {
+	return e.errorAt(fieldOffset(e.raw, field), format, args...)
+}
+
+// errorAtElement returns an error pointing at an element of an array field of the definition
+func (e *entry) errorAtElement(field string, index int, format string, args ...any) *Error {
+	return e.errorAt(elementOffset(e.raw, field, index), format, args...)
+}
+
+// Load reads and validates every .json file in the directory, in name order
+// All problems found are returned together
+func Load(dir string) (*Content, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no content files found in %s", dir)
+	}
+	slices.Sort(paths)
+
+	content := &Content{positions: make(map[string]*entry)}
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, content.parse(path, data)...)
+	}
+	errs = append(errs, content.validate()...)
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return content, nil
+}
+
+// parse decodes one content file, keeping track of where each definition starts
+func (c *Content) parse(path string, data []byte) []error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	syntaxError := func(err error) []error {
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			// The offset is just past the character that was wrong
+			line, column := lineColumn(data, max(int(syntax.Offset)-1, 0))
+			return []error{&Error{File: path, Line: line, Column: column, Message: syntax.Error()}}
+		}
+		line, column := lineColumn(data, int(decoder.InputOffset()))
+		return []error{&Error{File: path, Line: line, Column: column, Message: err.Error()}}
+	}
+	expect := func(delim json.Delim) []error {
+		token, err := decoder.Token()
+		if err != nil {
+			return syntaxError(err)
+		}
+		if token != delim {
+			return syntaxError(fmt.Errorf("expected %q, found %v", delim, token))
+		}
+		return nil
+	}
+
+	if errs := expect('{'); errs != nil {
+		return errs
+	}
+
+	var errs []error
+	for decoder.More() {
+		keyOffset := skipSeparators(data, int(decoder.InputOffset()))
+		token, err := decoder.Token()
+		if err != nil {
+			return append(errs, syntaxError(err)...)
+		}
+		key, _ := token.(string)
+		if key != "creatures" && key != "items" {
+			line, column := lineColumn(data, keyOffset)
+			return append(errs, &Error{
+				File: path, Line: line, Column: column,
+				Message: fmt.Sprintf("unknown section %q, expected \"creatures\" or \"items\"", key),
+			})
+		}
+
+		if syntaxErrs := expect('['); syntaxErrs != nil {
+			return append(errs, syntaxErrs...)
+		}
+		for decoder.More() {
+			e := &entry{file: path, data: data, offset: skipSeparators(data, int(decoder.InputOffset()))}
+			if err := decoder.Decode(&e.raw); err != nil {
+				return append(errs, syntaxError(err)...)
+			}
+			if err := c.add(key, e); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if syntaxErrs := expect(']'); syntaxErrs != nil {
+			return append(errs, syntaxErrs...)
+		}
+	}
+
+	if syntaxErrs := expect('}'); syntaxErrs != nil {
+		return append(errs, syntaxErrs...)
+	}
+	return errs
+}
+
+// add decodes a single definition into the section it was found in
+func (c *Content) add(section string, e *entry) error {
+	decoder := json.NewDecoder(bytes.NewReader(e.raw))
+	decoder.DisallowUnknownFields()
+
+	var creature Creature
+	var item Item
+	var name string
+	var err error
+	switch section {
+	case "creatures":
+		err = decoder.Decode(&creature)
+		name = creature.Name
+	case "items":
+		err = decoder.Decode(&item)
+		name = item.Name
+	}
+	if err != nil {
+		return decodeError(e, err)
+	}
+
+	if name == "" {
+		return e.errorAt(0, "%s definition has no name", strings.TrimSuffix(section, "s"))
+	}
+	if existing, exists := c.positions[name]; exists {
+		line, column := lineColumn(existing.data, existing.offset)
+		return e.errorAtField(
+			"name",
+			"duplicate definition of %q, first defined at %s:%d:%d",
+			name, existing.file, line, column,
+		)
+	}
+	c.positions[name] = e
+
+	if section == "creatures" {
+		c.Creatures = append(c.Creatures, creature)
+	} else {
+		c.Items = append(c.Items, item)
+	}
+	return nil
+}
+
+// decodeError points a decoding error at the field it came from
+func decodeError(e *entry, err error) error {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		// Nested fields (ie. drops.chance) point at the top level field they are in
+		field, _, _ := strings.Cut(typeError.Field, ".")
+		return e.errorAtField(
+			field,
+			"%s should be %s, found %s",
+			typeError.Field, typeError.Type, typeError.Value,
+		)
+	}
+
+	// Unknown fields are reported as: json: unknown field "name"
+	if field, found := strings.CutPrefix(err.Error(), "json: unknown field "); found {
+		field = strings.Trim(field, `"`)
+		return e.errorAtField(field, "unknown field %q", field)
+	}
+
+	return e.errorAt(0, "%v", err)
+}
+
+// fieldOffset returns the offset of the field's key in a JSON object,
+// or 0 if the object doesn't have the field
+func fieldOffset(raw json.RawMessage, field string) int {
+	offset, _ := findField(json.NewDecoder(bytes.NewReader(raw)), raw, field)
+	return offset
+}
+
+// elementOffset returns the offset of an element of an array field in a JSON object,
+// falling back to the offset of the field itself
+func elementOffset(raw json.RawMessage, field string, index int) int {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	offset, found := findField(decoder, raw, field)
+	if !found {
+		return 0
+	}
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return offset
+	}
+	for i := 0; decoder.More(); i++ {
+		if i == index {
+			return skipSeparators(raw, int(decoder.InputOffset()))
+		}
+		var element json.RawMessage
+		if err := decoder.Decode(&element); err != nil {
+			return offset
+		}
+	}
+	return offset
+}
+
+// findField reads the JSON object until the field's key, leaving the decoder at its value,
+// and returns the offset of the key
+func findField(decoder *json.Decoder, raw json.RawMessage, field string) (int, bool) {
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return 0, false
+	}
+	for decoder.More() {
+		offset := skipSeparators(raw, int(decoder.InputOffset()))
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, false
+		}
+		if token == field {
+			return offset, true
+		}
+
+		// Skip over the value, so it isn't mistaken for a key
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// skipSeparators skips the whitespace, commas and colons that start at the offset
+func skipSeparators(data []byte, offset int) int {
+	for offset < len(data) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// lineColumn converts a byte offset into a 1-based line and column
+func lineColumn(data []byte, offset int) (int, int) {
+	offset = min(offset, len(data))
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	column := offset - bytes.LastIndexByte(data[:offset], '\n')
+	return line, column
+}
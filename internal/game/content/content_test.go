@@ -0,0 +1,198 @@
+package content
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// load writes the content file to a directory of its own and loads it
+func load(t *testing.T, file string) (*Content, error) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "content.json"), []byte(file), 0o644); err != nil {
+		t.Fatalf("writing content: %v", err)
+	}
+	return Load(dir)
+}
+
+// contentErrors returns every content error Load reported
+func contentErrors(t *testing.T, err error) []*Error {
+	t.Helper()
+	joined, isJoined := err.(interface{ Unwrap() []error })
+	if !isJoined {
+		t.Fatalf("error = %v, want content errors", err)
+	}
+	errs := []*Error{}
+	for _, err := range joined.Unwrap() {
+		var contentErr *Error
+		if !errors.As(err, &contentErr) {
+			t.Fatalf("error %v has no position", err)
+		}
+		errs = append(errs, contentErr)
+	}
+	return errs
+}
+
+// The items every test file can refer to
+const testItems = `  "items": [
+    { "name": "potion", "displayName": "Potion", "sprite": "!", "effect": "heal" },
+    { "name": "sword", "displayName": "Sword", "sprite": "|", "damage": 3, "slots": ["right_hand"] }
+  ]`
+
+func TestLoadReportsWhereErrorsAre(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		line    int
+		column  int
+		message string
+	}{
+		{
+			name: "missing comma",
+			file: `{
+  "creatures": [
+    { "name": "rat", "sprite": "r" "hp": 5 }
+  ]
+}`,
+			line: 3, column: 36,
+			message: "invalid character",
+		},
+		{
+			name: "unknown section",
+			file: `{
+  "monsters": []
+}`,
+			line: 2, column: 3,
+			message: `unknown section "monsters"`,
+		},
+		{
+			name: "wrong type",
+			file: `{
+  "creatures": [
+    { "name": "rat", "sprite": "r",
+      "hp": "lots" }
+  ]
+}`,
+			line: 4, column: 7,
+			message: "hp should be int",
+		},
+		{
+			name: "unknown extends",
+			file: `{
+  "creatures": [
+    { "name": "rat", "sprite": "r", "hp": 5 },
+    { "name": "big_rat",
+      "extends": "mouse", "hp": 10 }
+  ],
+` + testItems + `
+}`,
+			line: 5, column: 7,
+			message: `creature "big_rat" extends unknown creature "mouse"`,
+		},
+		{
+			name: "extends itself",
+			file: `{
+  "creatures": [
+    { "name": "rat", "extends": "rat", "sprite": "r", "hp": 5 }
+  ]
+}`,
+			line: 3, column: 22,
+			message: `creature "rat" extends itself through rat -> rat`,
+		},
+		{
+			name: "bad slot",
+			file: `{
+  "items": [
+    { "name": "shield", "displayName": "Shield", "sprite": "]", "defense": 2,
+      "slots": ["left_hand", "tail"] }
+  ]
+}`,
+			line: 4, column: 30,
+			message: `item "shield" has unknown slot "tail"`,
+		},
+		{
+			name: "missing drop item",
+			file: `{
+  "creatures": [
+    { "name": "rat", "sprite": "r", "hp": 5,
+      "drops": [
+        { "item": "potion", "chance": 0.5 },
+        { "item": "cheese", "chance": 0.5 }
+      ] }
+  ],
+` + testItems + `
+}`,
+			line: 6, column: 9,
+			message: `creature "rat" drops unknown item "cheese"`,
+		},
+		{
+			name: "duplicate",
+			file: `{
+  "creatures": [
+    { "name": "rat", "sprite": "r", "hp": 5 },
+    { "name": "rat", "sprite": "R", "hp": 6 }
+  ]
+}`,
+			line: 4, column: 7,
+			message: `duplicate definition of "rat", first defined at`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := load(t, test.file)
+			errs := contentErrors(t, err)
+			if len(errs) != 1 {
+				t.Fatalf("errors = %v, want 1", errs)
+			}
+			got := errs[0]
+			if got.Line != test.line || got.Column != test.column ||
+				!strings.Contains(got.Message, test.message) {
+				t.Fatalf(
+					"error = %d:%d %s, want %d:%d %s",
+					got.Line, got.Column, got.Message, test.line, test.column, test.message,
+				)
+			}
+			if !strings.HasSuffix(got.File, "content.json") {
+				t.Fatalf("error file = %s, want content.json", got.File)
+			}
+		})
+	}
+}
+
+func TestLoadReportsEveryError(t *testing.T) {
+	_, err := load(t, `{
+  "creatures": [
+    { "name": "rat", "sprite": "rat", "hp": 5 },
+    { "name": "bat", "sprite": "b", "hp": 0 }
+  ]
+}`)
+	errs := contentErrors(t, err)
+	if len(errs) != 2 || errs[0].Line != 3 || errs[1].Line != 4 {
+		t.Fatalf("errors = %v, want one on each of lines 3 and 4", errs)
+	}
+}
+
+func TestLoadExtends(t *testing.T) {
+	content, err := load(t, `{
+  "creatures": [
+    { "name": "rat", "sprite": "r", "hp": 5, "depth": 2 },
+    { "name": "big_rat", "extends": "rat", "hp": 10 }
+  ],
+`+testItems+`
+}`)
+	if err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+
+	// The depth is inherited
+	if names := content.CreatureNames(1); len(names) != 0 {
+		t.Fatalf("creatures at depth 1 = %v, want none", names)
+	}
+	if names := content.CreatureNames(2); len(names) != 2 {
+		t.Fatalf("creatures at depth 2 = %v, want both", names)
+	}
+}
@@ -0,0 +1,137 @@
+package content
+
+import (
+	"ecs/internal/game/components"
+	"ecs/pkg/ecs"
+)
+
+// Register adds a prefab for every creature and item, so they can be spawned by name
+// Definitions that extend another become prefabs that extend its prefab
+func (c *Content) Register(registry *ecs.PrefabRegistry) error {
+	for _, creature := range c.Creatures {
+		if err := registry.Register(creaturePrefab(creature)); err != nil {
+			return c.positions[creature.Name].errorAtField("name", "%v", err)
+		}
+	}
+	for _, item := range c.Items {
+		if err := registry.Register(itemPrefab(item)); err != nil {
+			return c.positions[item.Name].errorAtField("name", "%v", err)
+		}
+	}
+	return nil
+}
+
+// creaturePrefab makes a prefab with a component for each field the creature sets
+// Creatures that don't extend another get the defaults of the fields they leave out
+func creaturePrefab(creature Creature) ecs.Prefab {
+	base := creature.Extends == ""
+	prefab := ecs.Prefab{
+		Name:    creature.Name,
+		Extends: creature.Extends,
+		Components: []func() ecs.Component{
+			func() ecs.Component { return &components.BlockerComponent{} },
+			func() ecs.Component { return &components.HostileComponent{} },
+		},
+	}
+
+	if creature.HP != nil {
+		hp := *creature.HP
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.HealthComponent{HP: hp, MaxHP: hp}
+		})
+	}
+	if creature.Strength != nil || base {
+		strength := valueOr(creature.Strength, 0)
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.StrengthComponent{Strength: strength}
+		})
+	}
+	if creature.Sprite != "" {
+		sprite := []rune(creature.Sprite)[0]
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.SpriteComponent{Char: sprite}
+		})
+	}
+	if creature.Speed != nil || base {
+		speed := valueOr(creature.Speed, components.NormalSpeed)
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.SpeedComponent{Speed: speed}
+		})
+	}
+
+	if creature.Drops != nil {
+		drops := make([]components.Drop, len(creature.Drops))
+		for i, drop := range creature.Drops {
+			drops[i] = components.Drop{Prefab: drop.Item, Chance: drop.Chance}
+		}
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.DropTableComponent{Drops: append([]components.Drop{}, drops...)}
+		})
+	}
+
+	return prefab
+}
+
+// itemPrefab makes a prefab with a component for each field the item sets
+func itemPrefab(item Item) ecs.Prefab {
+	prefab := ecs.Prefab{
+		Name:       item.Name,
+		Extends:    item.Extends,
+		Components: []func() ecs.Component{},
+	}
+
+	if item.Sprite != "" {
+		sprite := []rune(item.Sprite)[0]
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.SpriteComponent{Char: sprite}
+		})
+	}
+	if item.DisplayName != "" {
+		name, weight, value := item.DisplayName, valueOr(item.Weight, 0), valueOr(item.Value, 0)
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.ItemComponent{Name: name, Weight: weight, Value: value}
+		})
+	}
+
+	if item.Effect != "" {
+		effect, power := components.UsableEffect(item.Effect), valueOr(item.Power, 0)
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.UsableComponent{Effect: effect, Power: power}
+		})
+	}
+
+	if item.Slots != nil {
+		slots := make([]components.EquipmentSlot, len(item.Slots))
+		for i, slot := range item.Slots {
+			slots[i] = components.EquipmentSlot(slot)
+		}
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.EquippableComponent{
+				Slots: append([]components.EquipmentSlot{}, slots...),
+			}
+		})
+	}
+
+	if item.Damage != nil {
+		damage := *item.Damage
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.WeaponComponent{Damage: damage}
+		})
+	}
+
+	if item.Defense != nil {
+		defense := *item.Defense
+		prefab.Components = append(prefab.Components, func() ecs.Component {
+			return &components.ArmorComponent{Defense: defense}
+		})
+	}
+
+	return prefab
+}
+
+func valueOr(value *int, fallback int) int {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
@@ -0,0 +1,203 @@
+package content
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"ecs/internal/game/components"
+)
+
+var (
+	effects = []components.UsableEffect{
+		components.HealEffect,
+		components.DamageEffect,
+		components.RepairEffect,
+	}
+	slots = []components.EquipmentSlot{
+		components.Head,
+		components.Torso,
+		components.Legs,
+		components.Feet,
+		components.LeftHand,
+		components.RightHand,
+	}
+)
+
+// validate checks every definition on its own, and that what it extends exists
+// Fields are inherited when the definitions are spawned, so definitions that don't extend
+// another need the fields every creature or item has
+func (c *Content) validate() []error {
+	var errs []error
+	for _, creature := range c.Creatures {
+		if err := c.validateCreature(creature); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, item := range c.Items {
+		if err := c.validateItem(item); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (c *Content) validateCreature(creature Creature) error {
+	e := c.positions[creature.Name]
+	if err := c.validateExtends("creature", creature.Name, func(name string) (string, bool) {
+		parent, found := c.creature(name)
+		return parent.Extends, found
+	}); err != nil {
+		return err
+	}
+
+	switch {
+	case (creature.Sprite != "" || creature.Extends == "") &&
+		utf8.RuneCountInString(creature.Sprite) != 1:
+		return e.errorAtField(
+			"sprite",
+			"creature %q needs a single character sprite",
+			creature.Name,
+		)
+	case (creature.HP != nil || creature.Extends == "") && (creature.HP == nil || *creature.HP <= 0):
+		return e.errorAtField("hp", "creature %q needs hp above 0", creature.Name)
+	case creature.Strength != nil && *creature.Strength < 0:
+		return e.errorAtField(
+			"strength",
+			"creature %q can't have negative strength",
+			creature.Name,
+		)
+	case creature.Depth != nil && *creature.Depth < 1:
+		return e.errorAtField("depth", "creature %q needs depth of at least 1", creature.Name)
+	case creature.Speed != nil && *creature.Speed < 1:
+		return e.errorAtField("speed", "creature %q needs speed of at least 1", creature.Name)
+	}
+
+	for i, drop := range creature.Drops {
+		if _, found := c.item(drop.Item); !found {
+			return e.errorAtElement(
+				"drops", i,
+				"creature %q drops unknown item %q",
+				creature.Name, drop.Item,
+			)
+		}
+		if drop.Chance <= 0 || drop.Chance > 1 {
+			return e.errorAtElement(
+				"drops", i,
+				"drop chance of %q should be above 0 and at most 1, found %v",
+				drop.Item, drop.Chance,
+			)
+		}
+	}
+	return nil
+}
+
+func (c *Content) validateItem(item Item) error {
+	e := c.positions[item.Name]
+	if err := c.validateExtends("item", item.Name, func(name string) (string, bool) {
+		parent, found := c.item(name)
+		return parent.Extends, found
+	}); err != nil {
+		return err
+	}
+
+	switch {
+	case item.Extends == "" && item.DisplayName == "":
+		return e.errorAt(0, "item %q needs a displayName", item.Name)
+	case (item.Weight != nil || item.Value != nil) && item.DisplayName == "":
+		// They make up a single component, so they are inherited together
+		return e.errorAt(
+			0,
+			"item %q sets weight or value, so needs a displayName too",
+			item.Name,
+		)
+	case (item.Sprite != "" || item.Extends == "") && utf8.RuneCountInString(item.Sprite) != 1:
+		return e.errorAtField("sprite", "item %q needs a single character sprite", item.Name)
+	case item.Weight != nil && *item.Weight < 0:
+		return e.errorAtField("weight", "item %q can't have negative weight", item.Name)
+	case item.Value != nil && *item.Value < 0:
+		return e.errorAtField("value", "item %q can't have negative value", item.Name)
+	case item.Effect != "" && !slices.Contains(effects, components.UsableEffect(item.Effect)):
+		return e.errorAtField(
+			"effect",
+			"item %q has unknown effect %q, expected one of %s",
+			item.Name, item.Effect, joinNames(effects),
+		)
+	case item.Effect == "" && item.Power != nil:
+		return e.errorAtField("power", "item %q has power but no effect", item.Name)
+	case item.Extends == "" && (item.Damage != nil || item.Defense != nil) && len(item.Slots) == 0:
+		return e.errorAt(0, "item %q has damage or defense but no equipment slots", item.Name)
+	}
+
+	for i, slot := range item.Slots {
+		if !slices.Contains(slots, components.EquipmentSlot(slot)) {
+			return e.errorAtElement(
+				"slots", i,
+				"item %q has unknown slot %q, expected one of %s",
+				item.Name, slot, joinNames(slots),
+			)
+		}
+	}
+	return nil
+}
+
+// validateExtends checks that the definition the named one extends exists, and that it
+// doesn't extend itself through others. parent returns what a definition extends, if it
+// exists
+func (c *Content) validateExtends(kind, name string, parent func(string) (string, bool)) error {
+	e := c.positions[name]
+	extends, _ := parent(name)
+	if extends == "" {
+		return nil
+	}
+	if _, found := parent(extends); !found {
+		return e.errorAtField(
+			"extends",
+			"%s %q extends unknown %s %q",
+			kind, name, kind, extends,
+		)
+	}
+
+	// Definitions that lead into a circle without being part of it are left to the ones in it
+	visiting := []string{name}
+	for extends != "" && !slices.Contains(visiting, extends) {
+		visiting = append(visiting, extends)
+		extends, _ = parent(extends)
+	}
+	if extends == name {
+		return e.errorAtField(
+			"extends",
+			"%s %q extends itself through %s",
+			kind, name, strings.Join(append(visiting, name), " -> "),
+		)
+	}
+	return nil
+}
+
+func (c *Content) creature(name string) (Creature, bool) {
+	index := slices.IndexFunc(c.Creatures, func(creature Creature) bool {
+		return creature.Name == name
+	})
+	if index == -1 {
+		return Creature{}, false
+	}
+	return c.Creatures[index], true
+}
+
+func (c *Content) item(name string) (Item, bool) {
+	index := slices.IndexFunc(c.Items, func(item Item) bool {
+		return item.Name == name
+	})
+	if index == -1 {
+		return Item{}, false
+	}
+	return c.Items[index], true
+}
+
+func joinNames[T ~string](names []T) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = `"` + string(name) + `"`
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,95 @@
+package dungeon
+
+import (
+	"math/rand/v2"
+
+	"ecs/internal/game/resources"
+)
+
+// Leaves are split until they are about this size, so each still fits a room
+const (
+	minLeafWidth  = 8
+	minLeafHeight = 6
+)
+
+// bspNode is an area of the map, either split in two or holding a single room
+type bspNode struct {
+	area        Rect
+	left, right *bspNode
+	room        Rect
+}
+
+// generateBSP splits the map into areas recursively, puts a room in each leaf, and joins the
+// two halves of every split with a corridor
+func generateBSP(width, height int, rng *rand.Rand) *Level {
+	tileMap := resources.NewTileMap(width, height, resources.Wall)
+	root := &bspNode{area: Rect{X: 1, Y: 1, Width: width - 2, Height: height - 2}}
+	root.split(rng)
+
+	var rooms []Rect
+	root.carve(tileMap, rng, &rooms)
+	placeDoors(tileMap, rooms)
+	return &Level{Map: tileMap, Rooms: rooms, Start: rooms[0].Center()}
+}
+
+// split divides the node in two along its longer side, until the halves would be too small
+func (n *bspNode) split(rng *rand.Rand) {
+	canSplitWidth := n.area.Width >= minLeafWidth*2
+	canSplitHeight := n.area.Height >= minLeafHeight*2
+	if !canSplitWidth && !canSplitHeight {
+		return
+	}
+
+	vertical := canSplitWidth
+	if canSplitWidth && canSplitHeight {
+		vertical = n.area.Width >= n.area.Height
+	}
+
+	if vertical {
+		at := randomBetween(minLeafWidth, n.area.Width-minLeafWidth, rng)
+		n.left = &bspNode{area: Rect{X: n.area.X, Y: n.area.Y, Width: at, Height: n.area.Height}}
+		n.right = &bspNode{area: Rect{
+			X: n.area.X + at, Y: n.area.Y,
+			Width: n.area.Width - at, Height: n.area.Height,
+		}}
+	} else {
+		at := randomBetween(minLeafHeight, n.area.Height-minLeafHeight, rng)
+		n.left = &bspNode{area: Rect{X: n.area.X, Y: n.area.Y, Width: n.area.Width, Height: at}}
+		n.right = &bspNode{area: Rect{
+			X: n.area.X, Y: n.area.Y + at,
+			Width: n.area.Width, Height: n.area.Height - at,
+		}}
+	}
+	n.left.split(rng)
+	n.right.split(rng)
+}
+
+// carve digs a room in every leaf, and a corridor between the halves of every split
+func (n *bspNode) carve(tileMap *resources.TileMap, rng *rand.Rand, rooms *[]Rect) {
+	if n.left == nil {
+		// Leave a wall between neighbouring leaves
+		n.room = randomRoom(Rect{
+			X: n.area.X, Y: n.area.Y,
+			Width: n.area.Width - 1, Height: n.area.Height - 1,
+		}, rng)
+		carveRoom(tileMap, n.room)
+		*rooms = append(*rooms, n.room)
+		return
+	}
+
+	n.left.carve(tileMap, rng, rooms)
+	n.right.carve(tileMap, rng, rooms)
+	from, to := n.left.anyRoom(rng).Center(), n.right.anyRoom(rng).Center()
+	carveCorridor(tileMap, from, to, rng.IntN(2) == 0)
+}
+
+// anyRoom returns the room of a random leaf under the node
+func (n *bspNode) anyRoom(rng *rand.Rand) Rect {
+	if n.left == nil {
+		return n.room
+	}
+	if rng.IntN(2) == 0 {
+		return n.left.anyRoom(rng)
+	}
+	return n.right.anyRoom(rng)
+}
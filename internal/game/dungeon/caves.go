@@ -0,0 +1,90 @@
+package dungeon
+
+import (
+	"math/rand/v2"
+
+	"ecs/internal/game/resources"
+	"ecs/pkg/mathutils"
+)
+
+const (
+	caveWallChance = 0.45
+	caveSmoothing  = 5
+)
+
+// generateCaves fills the map with random walls and smooths them into caves with a cellular
+// automaton, starting the player in the largest cave
+// Caves that are cut off are joined up afterwards by Generate
+func generateCaves(width, height int, rng *rand.Rand) *Level {
+	tileMap := resources.NewTileMap(width, height, resources.Wall)
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			if rng.Float64() >= caveWallChance {
+				tileMap.Set(x, y, resources.Floor)
+			}
+		}
+	}
+
+	for range caveSmoothing {
+		tileMap = smoothCaves(tileMap)
+	}
+
+	return &Level{Map: tileMap, Start: largestCave(tileMap, rng)}
+}
+
+// smoothCaves returns the next generation of the automaton: a tile becomes a wall when most of
+// the tiles around it are walls, and the edge of the map is always wall
+func smoothCaves(tileMap *resources.TileMap) *resources.TileMap {
+	next := resources.NewTileMap(tileMap.Width, tileMap.Height, resources.Wall)
+	for y := 1; y < tileMap.Height-1; y++ {
+		for x := 1; x < tileMap.Width-1; x++ {
+			walls := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					// Off-map tiles count as wall
+					if tileMap.At(x+dx, y+dy) == resources.Wall {
+						walls++
+					}
+				}
+			}
+			if walls < 5 {
+				next.Set(x, y, resources.Floor)
+			}
+		}
+	}
+	return next
+}
+
+// largestCave returns a random tile in the largest area of connected floor
+// If the automaton left no floor at all, a small cave is dug in the middle of the map
+func largestCave(tileMap *resources.TileMap, rng *rand.Rand) mathutils.Point {
+	var largest []mathutils.Point
+	seen := map[mathutils.Point]bool{}
+	for y := range tileMap.Height {
+		for x := range tileMap.Width {
+			p := mathutils.Point{X: x, Y: y}
+			if seen[p] || !tileMap.Walkable(x, y) {
+				continue
+			}
+
+			var cave []mathutils.Point
+			for reached := range Reachable(tileMap, p) {
+				seen[reached] = true
+				cave = append(cave, reached)
+			}
+			if len(cave) > len(largest) {
+				largest = cave
+			}
+		}
+	}
+
+	if len(largest) == 0 {
+		center := Rect{X: tileMap.Width/2 - 2, Y: tileMap.Height/2 - 1, Width: 5, Height: 3}
+		carveRoom(tileMap, center)
+		return center.Center()
+	}
+
+	// Map iteration order is random, so pick from the cave in map order
+	sortPoints(largest)
+	return largest[rng.IntN(len(largest))]
+}
@@ -0,0 +1,219 @@
+package dungeon
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"ecs/internal/game/resources"
+	"ecs/pkg/mathutils"
+	"ecs/pkg/pathfinding"
+)
+
+// Algorithm picks how a level's layout is generated
+type Algorithm string
+
+const (
+	RoomsAndCorridors Algorithm = "rooms"
+	BSP               Algorithm = "bsp"
+	Caves             Algorithm = "caves"
+)
+
+// Algorithms lists every algorithm, in the order a random one is picked from
+var Algorithms = []Algorithm{RoomsAndCorridors, BSP, Caves}
+
+// Minimum level size, so there is room for a few rooms
+const (
+	MinWidth  = 20
+	MinHeight = 10
+)
+
+// Rect is an area of the map, ie. a room
+type Rect struct {
+	X, Y          int
+	Width, Height int
+}
+
+// Center returns the point in the middle of the rect
+func (r Rect) Center() mathutils.Point {
+	return mathutils.Point{X: r.X + r.Width/2, Y: r.Y + r.Height/2}
+}
+
+// Contains reports whether the point is inside the rect
+func (r Rect) Contains(p mathutils.Point) bool {
+	return p.X >= r.X && p.X < r.X+r.Width && p.Y >= r.Y && p.Y < r.Y+r.Height
+}
+
+// Intersects reports whether the rects overlap, or come within margin tiles of each other
+func (r Rect) Intersects(other Rect, margin int) bool {
+	return r.X-margin < other.X+other.Width && other.X-margin < r.X+r.Width &&
+		r.Y-margin < other.Y+other.Height && other.Y-margin < r.Y+r.Height
+}
+
+// Level is a generated map, along with where its rooms are and where the player starts
+// Caves have no rooms
+type Level struct {
+	Map   *resources.TileMap
+	Rooms []Rect
+	Start mathutils.Point
+	Down  mathutils.Point // Stairs down, once placed with AddStairs
+}
+
+// Generate creates a level with the algorithm, using rng for every random choice so
+// the same seed always generates the same level
+// Every walkable tile of the level can be reached from the start
+func Generate(algorithm Algorithm, width, height int, rng *rand.Rand) (*Level, error) {
+	if width < MinWidth || height < MinHeight {
+		return nil, fmt.Errorf("dungeon: level must be at least %dx%d", MinWidth, MinHeight)
+	}
+
+	var level *Level
+	switch algorithm {
+	case RoomsAndCorridors:
+		level = generateRooms(width, height, rng)
+	case BSP:
+		level = generateBSP(width, height, rng)
+	case Caves:
+		level = generateCaves(width, height, rng)
+	default:
+		return nil, fmt.Errorf("dungeon: unknown algorithm %q", algorithm)
+	}
+
+	connect(level.Map, level.Start)
+	return level, nil
+}
+
+// AddStairs puts the stairs down on the walkable tile furthest from the start, and the
+// stairs up on the start itself when the level has one above it
+func (l *Level) AddStairs(up bool) {
+	distances := Distances(l.Map, l.Start)
+
+	// Scan in map order so ties are broken the same way every time
+	furthest, furthestDistance := l.Start, 0
+	for y := range l.Map.Height {
+		for x := range l.Map.Width {
+			if distance, reached := distances.Distance(x, y); reached && distance > furthestDistance {
+				furthest, furthestDistance = mathutils.Point{X: x, Y: y}, distance
+			}
+		}
+	}
+
+	l.Down = furthest
+	l.Map.Set(furthest.X, furthest.Y, resources.StairsDown)
+	if up {
+		l.Map.Set(l.Start.X, l.Start.Y, resources.StairsUp)
+	}
+}
+
+// carveRoom turns the area of the room into floor
+func carveRoom(tileMap *resources.TileMap, room Rect) {
+	for y := room.Y; y < room.Y+room.Height; y++ {
+		for x := room.X; x < room.X+room.Width; x++ {
+			tileMap.Set(x, y, resources.Floor)
+		}
+	}
+}
+
+// carveCorridor digs an L shaped corridor between the points,
+// going horizontally or vertically first
+func carveCorridor(tileMap *resources.TileMap, from, to mathutils.Point, horizontalFirst bool) {
+	corner := mathutils.Point{X: to.X, Y: from.Y}
+	if !horizontalFirst {
+		corner = mathutils.Point{X: from.X, Y: to.Y}
+	}
+	carveLine(tileMap, from, corner)
+	carveLine(tileMap, corner, to)
+}
+
+// carveLine digs a straight horizontal or vertical line between the points
+func carveLine(tileMap *resources.TileMap, from, to mathutils.Point) {
+	for x := min(from.X, to.X); x <= max(from.X, to.X); x++ {
+		for y := min(from.Y, to.Y); y <= max(from.Y, to.Y); y++ {
+			if !tileMap.At(x, y).Passable() {
+				tileMap.Set(x, y, resources.Floor)
+			}
+		}
+	}
+}
+
+// Reachable returns every passable tile that can be walked to from the start, moving in the
+// four cardinal directions and opening any doors on the way
+func Reachable(tileMap *resources.TileMap, start mathutils.Point) map[mathutils.Point]bool {
+	distances := Distances(tileMap, start)
+	reached := map[mathutils.Point]bool{}
+	for y := range tileMap.Height {
+		for x := range tileMap.Width {
+			if _, reachable := distances.Distance(x, y); reachable {
+				reached[mathutils.Point{X: x, Y: y}] = true
+			}
+		}
+	}
+	return reached
+}
+
+// Distances returns how many steps it takes to walk to every reachable tile from the start
+// Nothing is reachable from a start that can't be stood on
+func Distances(tileMap *resources.TileMap, start mathutils.Point) *pathfinding.DijkstraMap {
+	grid := pathfinding.Grid{
+		Width:  tileMap.Width,
+		Height: tileMap.Height,
+		Blocked: func(x, y int) bool {
+			return !tileMap.Passable(x, y)
+		},
+	}
+	if !tileMap.Passable(start.X, start.Y) {
+		return grid.DijkstraMap()
+	}
+	return grid.DijkstraMap(start)
+}
+
+// connect digs corridors from every passable tile that can't be reached from the
+// start to the nearest one that can, until the whole level is connected
+func connect(tileMap *resources.TileMap, start mathutils.Point) {
+	for {
+		reached := Reachable(tileMap, start)
+		unreached, found := firstUnreached(tileMap, reached)
+		if !found {
+			return
+		}
+
+		// Scan in map order so the nearest tile, and the level, is the same every time
+		nearest, nearestDistance := start, -1
+		for y := range tileMap.Height {
+			for x := range tileMap.Width {
+				p := mathutils.Point{X: x, Y: y}
+				if !reached[p] {
+					continue
+				}
+				distance := mathutils.Manhattan(p, unreached)
+				if nearestDistance == -1 || distance < nearestDistance {
+					nearest, nearestDistance = p, distance
+				}
+			}
+		}
+		carveCorridor(tileMap, unreached, nearest, true)
+	}
+}
+
+func firstUnreached(
+	tileMap *resources.TileMap,
+	reached map[mathutils.Point]bool,
+) (mathutils.Point, bool) {
+	for y := range tileMap.Height {
+		for x := range tileMap.Width {
+			p := mathutils.Point{X: x, Y: y}
+			if tileMap.Passable(x, y) && !reached[p] {
+				return p, true
+			}
+		}
+	}
+	return mathutils.Point{}, false
+}
+
+// sortPoints sorts the points in map order, row by row
+func sortPoints(points []mathutils.Point) {
+	slices.SortFunc(points, func(a, b mathutils.Point) int {
+		return cmp.Or(cmp.Compare(a.Y, b.Y), cmp.Compare(a.X, b.X))
+	})
+}
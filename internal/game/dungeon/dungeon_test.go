@@ -0,0 +1,71 @@
+package dungeon
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"ecs/internal/game/resources"
+	"ecs/pkg/mathutils"
+)
+
+const (
+	testWidth  = 50
+	testHeight = 20
+	testSeeds  = 50
+)
+
+func generate(t *testing.T, algorithm Algorithm, seed uint64) *Level {
+	t.Helper()
+	level, err := Generate(algorithm, testWidth, testHeight, rand.New(rand.NewPCG(seed, seed)))
+	if err != nil {
+		t.Fatalf("generating: %v", err)
+	}
+	level.AddStairs(true)
+	return level
+}
+
+func TestLevelsAreConnected(t *testing.T) {
+	for _, algorithm := range Algorithms {
+		for seed := range uint64(testSeeds) {
+			t.Run(fmt.Sprintf("%s/%d", algorithm, seed), func(t *testing.T) {
+				level := generate(t, algorithm, seed)
+				reached := Reachable(level.Map, level.Start)
+
+				for y := range level.Map.Height {
+					for x := range level.Map.Width {
+						p := mathutils.Point{X: x, Y: y}
+						if level.Map.Passable(x, y) && !reached[p] {
+							t.Fatalf("tile %v can't be reached from the start\n%s", p, level.Map)
+						}
+					}
+				}
+				for _, stairs := range []resources.Tile{resources.StairsUp, resources.StairsDown} {
+					x, y, found := level.Map.Find(stairs)
+					if !found || !reached[mathutils.Point{X: x, Y: y}] {
+						t.Fatalf("%s can't be reached from the start\n%s", stairs, level.Map)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSameSeedGeneratesSameLevel(t *testing.T) {
+	for _, algorithm := range Algorithms {
+		for seed := range uint64(testSeeds) {
+			t.Run(fmt.Sprintf("%s/%d", algorithm, seed), func(t *testing.T) {
+				first, second := generate(t, algorithm, seed), generate(t, algorithm, seed)
+				if first.Map.String() != second.Map.String() {
+					t.Fatalf("levels differ:\n%s\n%s", first.Map, second.Map)
+				}
+				if first.Start != second.Start || first.Down != second.Down {
+					t.Fatalf(
+						"start and stairs differ: %v %v, %v %v",
+						first.Start, first.Down, second.Start, second.Down,
+					)
+				}
+			})
+		}
+	}
+}
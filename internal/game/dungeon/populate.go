@@ -0,0 +1,84 @@
+package dungeon
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"ecs/internal/game/resources"
+	"ecs/pkg/ecs"
+	"ecs/pkg/mathutils"
+)
+
+// Enemies are never placed this close to where the player starts
+const safeDistance = 6
+
+// Spawner creates entities from prefabs on the map, ie. an EntityService
+type Spawner interface {
+	Spawn(name string, x, y int) (ecs.Entity, error)
+}
+
+// Population is what to place on a level
+// Each enemy and item is a random pick from its list of prefabs
+type Population struct {
+	Enemies      int
+	Items        int
+	EnemyPrefabs []string
+	ItemPrefabs  []string
+}
+
+// Populate spawns the enemies and items on free floor tiles, one entity per tile
+// The start is left for the player, and enemies are kept out of the starting room and
+// away from it
+func Populate(level *Level, spawner Spawner, population Population, rng *rand.Rand) error {
+	free := []mathutils.Point{}
+	for y := range level.Map.Height {
+		for x := range level.Map.Width {
+			p := mathutils.Point{X: x, Y: y}
+			// Doors and stairs are left clear so they never get blocked
+			if p != level.Start && level.Map.At(x, y) == resources.Floor {
+				free = append(free, p)
+			}
+		}
+	}
+
+	startRoom := Rect{}
+	for _, room := range level.Rooms {
+		if room.Contains(level.Start) {
+			startRoom = room
+			break
+		}
+	}
+	awayFromStart := func(p mathutils.Point) bool {
+		return !startRoom.Contains(p) && mathutils.Manhattan(p, level.Start) >= safeDistance
+	}
+
+	spawn := func(count int, prefabs []string, allowed func(mathutils.Point) bool) error {
+		if count > 0 && len(prefabs) == 0 {
+			return fmt.Errorf("dungeon: no prefabs to pick from")
+		}
+		for range count {
+			candidates := []int{}
+			for i, p := range free {
+				if allowed(p) {
+					candidates = append(candidates, i)
+				}
+			}
+			if len(candidates) == 0 {
+				return nil
+			}
+
+			index := candidates[rng.IntN(len(candidates))]
+			p := free[index]
+			free = append(free[:index], free[index+1:]...)
+			if _, err := spawner.Spawn(prefabs[rng.IntN(len(prefabs))], p.X, p.Y); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := spawn(population.Enemies, population.EnemyPrefabs, awayFromStart); err != nil {
+		return err
+	}
+	return spawn(population.Items, population.ItemPrefabs, func(mathutils.Point) bool { return true })
+}
@@ -0,0 +1,99 @@
+package dungeon
+
+import (
+	"math/rand/v2"
+
+	"ecs/internal/game/resources"
+	"ecs/pkg/mathutils"
+)
+
+const (
+	maxRooms      = 12
+	roomAttempts  = 200
+	minRoomWidth  = 4
+	maxRoomWidth  = 10
+	minRoomHeight = 3
+	maxRoomHeight = 6
+)
+
+// generateRooms scatters rooms that don't overlap across the map, and joins each one to
+// the room before it with a corridor
+func generateRooms(width, height int, rng *rand.Rand) *Level {
+	tileMap := resources.NewTileMap(width, height, resources.Wall)
+	var rooms []Rect
+
+	for attempt := 0; attempt < roomAttempts && len(rooms) < maxRooms; attempt++ {
+		room := randomRoom(Rect{X: 1, Y: 1, Width: width - 2, Height: height - 2}, rng)
+		overlaps := false
+		for _, other := range rooms {
+			if room.Intersects(other, 1) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+
+		carveRoom(tileMap, room)
+		if len(rooms) > 0 {
+			carveCorridor(tileMap, rooms[len(rooms)-1].Center(), room.Center(), rng.IntN(2) == 0)
+		}
+		rooms = append(rooms, room)
+	}
+
+	placeDoors(tileMap, rooms)
+	return &Level{Map: tileMap, Rooms: rooms, Start: rooms[0].Center()}
+}
+
+// randomRoom picks a room that fits inside the area
+// Rooms shrink to fit areas smaller than the usual room size
+func randomRoom(area Rect, rng *rand.Rand) Rect {
+	roomWidth := randomBetween(min(minRoomWidth, area.Width), min(maxRoomWidth, area.Width), rng)
+	roomHeight := randomBetween(
+		min(minRoomHeight, area.Height),
+		min(maxRoomHeight, area.Height),
+		rng,
+	)
+	return Rect{
+		X:      area.X + rng.IntN(area.Width-roomWidth+1),
+		Y:      area.Y + rng.IntN(area.Height-roomHeight+1),
+		Width:  roomWidth,
+		Height: roomHeight,
+	}
+}
+
+// placeDoors puts a door where a corridor enters a room through a gap in its wall
+func placeDoors(tileMap *resources.TileMap, rooms []Rect) {
+	for _, room := range rooms {
+		for x := room.X; x < room.X+room.Width; x++ {
+			placeDoor(tileMap, mathutils.Point{X: x, Y: room.Y - 1}, true)
+			placeDoor(tileMap, mathutils.Point{X: x, Y: room.Y + room.Height}, true)
+		}
+		for y := room.Y; y < room.Y+room.Height; y++ {
+			placeDoor(tileMap, mathutils.Point{X: room.X - 1, Y: y}, false)
+			placeDoor(tileMap, mathutils.Point{X: room.X + room.Width, Y: y}, false)
+		}
+	}
+}
+
+// placeDoor turns a floor tile into a door if it has wall on both sides along the room's edge
+func placeDoor(tileMap *resources.TileMap, p mathutils.Point, horizontalWall bool) {
+	if tileMap.At(p.X, p.Y) != resources.Floor {
+		return
+	}
+	if horizontalWall {
+		if tileMap.At(p.X-1, p.Y) == resources.Wall && tileMap.At(p.X+1, p.Y) == resources.Wall {
+			tileMap.Set(p.X, p.Y, resources.ClosedDoor)
+		}
+		return
+	}
+	if tileMap.At(p.X, p.Y-1) == resources.Wall && tileMap.At(p.X, p.Y+1) == resources.Wall {
+		tileMap.Set(p.X, p.Y, resources.ClosedDoor)
+	}
+}
+
+// randomBetween returns a number from low to high, inclusive
+func randomBetween(low, high int, rng *rand.Rand) int {
+	return low + rng.IntN(high-low+1)
+}
@@ -7,13 +7,24 @@ import (
 )
 
 type EntityService struct {
-	world  *ecs.World
-	logger *log.Logger
+	world   *ecs.World
+	prefabs *ecs.PrefabRegistry
+	logger  *log.Logger
 }
 
-func NewEntityService(world *ecs.World, logger *log.Logger) *EntityService {
+func NewEntityService(
+	world *ecs.World,
+	prefabs *ecs.PrefabRegistry,
+	logger *log.Logger,
+) *EntityService {
 	return &EntityService{
-		world:  world,
-		logger: logger,
+		world:   world,
+		prefabs: prefabs,
+		logger:  logger,
 	}
 }
+
+// Prefabs returns the registry of prefabs the service spawns from
+func (es *EntityService) Prefabs() *ecs.PrefabRegistry {
+	return es.prefabs
+}
@@ -4,74 +4,73 @@ import (
 	"fmt"
 
 	"ecs/internal/game/components"
+	"ecs/internal/game/events"
 	"ecs/pkg/ecs"
 )
 
-func (g *Game) entityDefeatedEventHandler(event ecs.Event) {
+func (g *Game) entityDefeatedEventHandler(event events.EntityDefeatedEventData) {
 	g.turnManager.RemoveEntity(event.Entity)
 
 	// Check if player was defeated
-	if g.world.ComponentManager.HasComponent(event.Entity, components.PlayerControlled) {
-		g.gameOver = true
-		g.statusMessage = "Game Over! You were defeated! Press Q to quit."
+	if ecs.Has[components.PlayerControlledComponent](g.world, event.Entity) {
+		g.state().GameOver = true
+		g.state().StatusMessage = "Game Over! You were defeated! Press Q to quit."
 	} else {
-		g.statusMessage = fmt.Sprintf("You defeated entity %d!", event.Entity)
+		g.state().StatusMessage = fmt.Sprintf("You defeated entity %d!", event.Entity)
 	}
 }
 
-func (g *Game) itemPickedUpEventHandler(event ecs.Event) {
-	itemID, ok := event.Data["item"].(ecs.Entity)
-	if ok {
-		if itemComp, hasItem := g.world.ComponentManager.GetComponent(itemID, components.Item); hasItem {
-			item := itemComp.(*components.ItemComponent)
-			g.statusMessage = fmt.Sprintf("Picked up %s", item.Name)
-		}
+func (g *Game) itemPickedUpEventHandler(event events.ItemPickedUpEventData) {
+	if item, hasItem := ecs.Get[components.ItemComponent](g.world, event.Item); hasItem {
+		g.state().StatusMessage = fmt.Sprintf("Picked up %s", item.Name)
 	}
 }
 
-func (g *Game) itemUsedEventHandler(event ecs.Event) {
-	itemID, ok := event.Data["item"].(ecs.Entity)
-	if ok {
-		if itemComp, hasItem := g.world.ComponentManager.GetComponent(itemID, components.Item); hasItem {
-			item := itemComp.(*components.ItemComponent)
-			g.statusMessage = fmt.Sprintf("Used %s", item.Name)
-			if target, ok := event.Data["target"].(ecs.Entity); ok {
-				if healthComp, hasHealth := g.world.ComponentManager.GetComponent(target, components.Health); hasHealth {
-					health := healthComp.(*components.HealthComponent)
-					g.statusMessage += fmt.Sprintf(
-						" on %d (HP %d/%d)",
-						target,
-						health.HP,
-						health.MaxHP,
-					)
-				}
-			}
-		}
+func (g *Game) itemUsedEventHandler(event events.ItemUsedEventData) {
+	g.state().StatusMessage = fmt.Sprintf("Used %s", event.ItemName)
+	if health, hasHealth := ecs.Get[components.HealthComponent](g.world, event.Target); hasHealth {
+		g.state().StatusMessage += fmt.Sprintf(
+			" on %d (HP %d/%d)",
+			event.Target,
+			health.HP,
+			health.MaxHP,
+		)
 	}
 }
 
-func (g *Game) itemEquippedEventHandler(event ecs.Event) {
-	itemID, ok1 := event.Data["item"].(ecs.Entity)
-	targetID, ok2 := event.Data["target"].(ecs.Entity)
-	if ok1 && ok2 {
-		if itemComp, hasItem := g.world.ComponentManager.GetComponent(itemID, components.Item); hasItem {
-			item := itemComp.(*components.ItemComponent)
-			g.statusMessage = fmt.Sprintf("Equipped %s on %d", item.Name, targetID)
+func (g *Game) itemEquippedEventHandler(event events.ItemEquippedEventData) {
+	if item, hasItem := ecs.Get[components.ItemComponent](g.world, event.Item); hasItem {
+		g.state().StatusMessage = fmt.Sprintf("Equipped %s on %d", item.Name, event.Target)
+	}
+}
+
+func (g *Game) itemUnequippedEventHandler(event events.ItemUnequippedEventData) {
+	if item, hasItem := ecs.Get[components.ItemComponent](g.world, event.Item); hasItem {
+		g.state().StatusMessage = fmt.Sprintf("Unequipped %s", item.Name)
+	}
+}
+
+func (g *Game) moveBlockedEventHandler(event events.MoveBlockedEventData) {
+	if !ecs.Has[components.PlayerControlledComponent](g.world, event.Entity) {
+		return
+	}
 
-		}
+	switch event.Reason {
+	case events.BlockedByBounds:
+		g.state().StatusMessage = "Cannot move out of bounds"
+	case events.BlockedByTerrain:
+		g.state().StatusMessage = fmt.Sprintf("Blocked by the %s", g.GetTileMap().At(event.X, event.Y))
+	case events.BlockedByEntity:
+		g.state().StatusMessage = fmt.Sprintf("Blocked by entity %d", event.Blocker)
 	}
 }
 
-func (g *Game) itemUnequippedEventHandler(event ecs.Event) {
-	itemID, ok := event.Data["item"].(ecs.Entity)
-	if ok {
-		if itemComp, hasItem := g.world.ComponentManager.GetComponent(itemID, components.Item); hasItem {
-			item := itemComp.(*components.ItemComponent)
-			g.statusMessage = fmt.Sprintf("Unequipped %s", item.Name)
-		}
+func (g *Game) doorOpenedEventHandler(event events.DoorOpenedEventData) {
+	if ecs.Has[components.PlayerControlledComponent](g.world, event.Entity) {
+		g.state().StatusMessage = "You open the door"
 	}
 }
 
-func (g *Game) debugStatusEventHandler(event ecs.Event) {
-	g.statusMessage = fmt.Sprintf("Debug event: %s", event.Data["message"])
+func (g *Game) debugStatusEventHandler(event events.DebugStatusMessageEventData) {
+	g.state().StatusMessage = fmt.Sprintf("Debug event: %s", event.Message)
 }
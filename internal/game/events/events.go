@@ -2,40 +2,79 @@ package events
 
 import "ecs/pkg/ecs"
 
+// Events are delivered by type, so each struct here is its own event
+// Emit them with ecs.Emit and receive them with ecs.Subscribe
+
+type EntityMovedEventData struct {
+	Entity     ecs.Entity
+	OldX, OldY int
+	NewX, NewY int
+}
+
+// MoveBlockedReason is why a move didn't happen
+type MoveBlockedReason int
+
 const (
-	EntityMoved    ecs.EventType = "entity_moved"
-	EntityAttacked ecs.EventType = "entity_attacked"
-	EntityDefeated ecs.EventType = "entity_defeated"
-	HealthChanged  ecs.EventType = "health_changed"
-	TurnEnded      ecs.EventType = "turn_ended"
-	ItemPickedUp   ecs.EventType = "item_picked_up"
-	ItemUsed       ecs.EventType = "item_used"
-	ItemEquipped   ecs.EventType = "item_equipped"
-	ItemUnequipped ecs.EventType = "item_unequipped"
-
-	DebugStatusMessage ecs.EventType = "debug_status_message"
+	BlockedByBounds  MoveBlockedReason = iota // The move was off the map
+	BlockedByTerrain                          // The tile can't be walked on, ie. a wall
+	BlockedByEntity                           // Another entity that blocks is on the tile
 )
 
-// TODO: We need a way for events to be a bit more typed and have a consistent structure?
-type EntityMovedEventData struct {
+type MoveBlockedEventData struct {
+	Entity  ecs.Entity // Entity that tried to move
+	X, Y    int        // Tile it tried to move onto
+	Reason  MoveBlockedReason
+	Blocker ecs.Entity // Entity in the way when blocked by an entity, otherwise -1
+}
+
+type DoorOpenedEventData struct {
+	Entity ecs.Entity // Entity that opened the door
+	X, Y   int
 }
 
 type EntityAttackedEventData struct {
+	Attacker ecs.Entity
+	Target   ecs.Entity
+	Damage   int
 }
 
 type EntityDefeatedEventData struct {
+	Entity ecs.Entity
 }
 
 type HealthChangedEventData struct {
+	Entity    ecs.Entity
+	HP, MaxHP int
 }
 
 type TurnEndedEventData struct {
+	Entity ecs.Entity
 }
 
 type ItemPickedUpEventData struct {
+	Entity ecs.Entity // Entity that picked the item up
+	Item   ecs.Entity
 }
 
 type ItemUsedEventData struct {
+	Entity   ecs.Entity // Entity that used the item
+	Item     ecs.Entity // Item that was used, which is despawned by the time this is handled
+	ItemName string
+	Target   ecs.Entity
+}
+
+type ItemEquippedEventData struct {
+	Entity ecs.Entity // Entity that equipped the item
+	Item   ecs.Entity
+	Target ecs.Entity
+}
+
+type ItemUnequippedEventData struct {
+	Entity ecs.Entity // Entity that unequipped the item
 	Item   ecs.Entity
 	Target ecs.Entity
 }
+
+type DebugStatusMessageEventData struct {
+	Message string
+}
@@ -0,0 +1,85 @@
+package fov
+
+// octants maps the one octant that is scanned onto each of the eight around the origin
+// Each column is the xx, xy, yx and yy multipliers of one octant
+var octants = [4][8]int{
+	{1, 0, 0, -1, -1, 0, 0, 1},
+	{0, 1, -1, 0, 0, -1, 1, 0},
+	{0, 1, 1, 0, 0, -1, -1, 0},
+	{1, 0, 0, 1, -1, 0, 0, -1},
+}
+
+// Compute calls visit for every tile within the radius that can be seen from the origin,
+// using recursive shadowcasting. The origin itself is always visible
+// Tiles that block sight are visible, but hide the tiles behind them
+// visit can be called more than once for the same tile, and for tiles off the map
+func Compute(originX, originY, radius int, blocksSight func(x, y int) bool, visit func(x, y int)) {
+	visit(originX, originY)
+	for octant := range 8 {
+		c := caster{
+			originX: originX, originY: originY, radius: radius,
+			xx: octants[0][octant], xy: octants[1][octant],
+			yx: octants[2][octant], yy: octants[3][octant],
+			blocksSight: blocksSight, visit: visit,
+		}
+		c.cast(1, 1.0, 0.0)
+	}
+}
+
+// caster scans a single octant
+type caster struct {
+	originX, originY int
+	radius           int
+	xx, xy, yx, yy   int
+	blocksSight      func(x, y int) bool
+	visit            func(x, y int)
+}
+
+// cast scans the octant row by row from the row given, between the start and end slopes,
+// and scans again past every run of tiles that blocks sight
+func (c caster) cast(row int, start, end float64) {
+	if start < end {
+		return
+	}
+
+	radiusSquared := c.radius * c.radius
+	for distance := row; distance <= c.radius; distance++ {
+		dy := -distance
+		blocked := false
+		nextStart := start
+		for dx := -distance; dx <= 0; dx++ {
+			x := c.originX + dx*c.xx + dy*c.xy
+			y := c.originY + dx*c.yx + dy*c.yy
+			leftSlope := (float64(dx) - 0.5) / (float64(dy) + 0.5)
+			rightSlope := (float64(dx) + 0.5) / (float64(dy) - 0.5)
+			if start < rightSlope {
+				continue
+			}
+			if end > leftSlope {
+				break
+			}
+
+			if dx*dx+dy*dy <= radiusSquared {
+				c.visit(x, y)
+			}
+
+			if blocked {
+				if c.blocksSight(x, y) {
+					nextStart = rightSlope
+					continue
+				}
+				blocked = false
+				start = nextStart
+			} else if c.blocksSight(x, y) && distance < c.radius {
+				// Everything behind this tile is in shadow, so scan the rest of the
+				// octant past it separately
+				blocked = true
+				c.cast(distance+1, start, leftSlope)
+				nextStart = rightSlope
+			}
+		}
+		if blocked {
+			return
+		}
+	}
+}
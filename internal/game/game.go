@@ -1,16 +1,21 @@
 package game
 
 import (
+	"fmt"
 	"log"
 	"slices"
+	"time"
 
 	"ecs/internal/game/components"
+	"ecs/internal/game/content"
+	"ecs/internal/game/dungeon"
 	"ecs/internal/game/entityservice"
-	"ecs/internal/game/events"
+	"ecs/internal/game/prefabs"
+	"ecs/internal/game/resources"
+	"ecs/internal/game/spatial"
 	"ecs/internal/game/systems"
 	"ecs/internal/turnmanager"
 	"ecs/pkg/ecs"
-	"ecs/pkg/mathutils"
 )
 
 // Game coordinates all game systems
@@ -18,115 +23,181 @@ type Game struct {
 	world         *ecs.World
 	turnManager   *turnmanager.TurnManager
 	aiSystem      *systems.AISystem
+	fovSystem     *systems.FOVSystem
+	index         *spatial.Index
 	entityService *entityservice.EntityService
-	width         int
-	height        int
-	gameOver      bool
-	statusMessage string
+	contentDir    string
+
+	// Creatures and items that are spawned on generated levels
+	content *content.Content
+	// How levels are generated, picked at random for each level when empty
+	algorithm dungeon.Algorithm
 
 	logger *log.Logger
 }
 
-func NewGame(logger *log.Logger) *Game {
+// NewGame creates a game whose creatures and items are loaded from the content directory
+func NewGame(logger *log.Logger, contentDir string) (*Game, error) {
 	world := ecs.NewWorld(logger)
 
+	// Prefabs are built in or loaded from content, and spawned by name
+	prefabRegistry := ecs.NewPrefabRegistry()
+	if err := prefabs.Register(prefabRegistry); err != nil {
+		return nil, err
+	}
+	gameContent, err := content.Load(contentDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading content: %w", err)
+	}
+	if err := gameContent.Register(prefabRegistry); err != nil {
+		return nil, fmt.Errorf("loading content: %w", err)
+	}
+	if !prefabRegistry.Has(prefabs.StartingSword) {
+		return nil, fmt.Errorf("loading content: no %q item is defined", prefabs.StartingSword)
+	}
+
 	// Create system instances
 	aiSystem := &systems.AISystem{}
+	fovSystem := &systems.FOVSystem{}
+	index := spatial.NewIndex(world)
+
+	// Register core ECS systems, which all resolve intents
+	// Movement goes first so attacks, pickups and item use see where everyone ended up,
+	// and the spatial index catches up with it before anything looks up what is where
+	world.AddSystem(
+		&systems.MovementSystem{Index: index, BumpToAttack: true},
+		ecs.Named("movement"),
+		ecs.InStage(ecs.Resolve),
+	)
+	world.AddSystem(
+		index,
+		ecs.Named("spatial"),
+		ecs.InStage(ecs.Resolve),
+		ecs.After("movement"),
+	)
+	world.AddSystem(
+		&systems.CombatSystem{},
+		ecs.Named("combat"),
+		ecs.InStage(ecs.Resolve),
+		ecs.After("movement"),
+	)
+	world.AddSystem(
+		&systems.InventorySystem{Index: index},
+		ecs.Named("inventory"),
+		ecs.InStage(ecs.Resolve),
+		ecs.After("spatial"),
+	)
+	world.AddSystem(
+		&systems.UsableSystem{},
+		ecs.Named("usable"),
+		ecs.InStage(ecs.Resolve),
+		ecs.After("combat", "inventory"),
+	)
+	world.AddSystem(
+		&systems.EquipmentSystem{},
+		ecs.Named("equipment"),
+		ecs.InStage(ecs.Resolve),
+		ecs.After("inventory"),
+	)
+	world.AddSystem(
+		fovSystem,
+		ecs.Named("fov"),
+		ecs.InStage(ecs.PostUpdate),
+	)
+	world.AddSystem(
+		&systems.DefeatSystem{Prefabs: prefabRegistry},
+		ecs.Named("defeat"),
+		ecs.InStage(ecs.Cleanup),
+	)
+	if err := world.BuildSchedule(); err != nil {
+		return nil, err
+	}
 
-	// Register core ECS systems
-	world.AddSystem(&systems.MovementSystem{})
-	world.AddSystem(&systems.CombatSystem{})
-	world.AddSystem(&systems.InventorySystem{})
-	world.AddSystem(&systems.UsableSystem{})
-	world.AddSystem(&systems.EquipmentSystem{})
+	// Every creature with health takes turns, so keep the turn order in sync with them
+	turnManager := turnmanager.NewTurnManager(world, func(entity ecs.Entity) int {
+		if speed, hasSpeed := ecs.Get[components.SpeedComponent](world, entity); hasSpeed {
+			return speed.Speed
+		}
+		return components.NormalSpeed
+	})
+	ecs.OnAdd(world, func(entity ecs.Entity, _ *components.HealthComponent) {
+		turnManager.AddEntity(entity)
+	})
+	ecs.OnRemove(world, func(entity ecs.Entity, _ *components.HealthComponent) {
+		turnManager.RemoveEntity(entity)
+	})
+
+	// World-level state lives in resources, so systems can use it too
+	// The tile map is generated by Initialize
+	ecs.SetResource(world, &resources.GameState{
+		GameOver:      false,
+		StatusMessage: "Use arrow keys to move, space to pick up items, 1-9 to use items, Q to quit",
+	})
+	ecs.SetResource(world, resources.NewRNG(uint64(time.Now().UnixNano())))
 
 	return &Game{
 		world:         world,
-		turnManager:   turnmanager.NewTurnManager(world),
+		turnManager:   turnManager,
 		aiSystem:      aiSystem,
-		entityService: entityservice.NewEntityService(world, logger),
-		width:         30,
-		height:        10,
-		gameOver:      false,
-		statusMessage: "Use arrow keys to move, space to pick up items, 1-9 to use items, Q to quit",
+		fovSystem:     fovSystem,
+		index:         index,
+		entityService: entityservice.NewEntityService(world, prefabRegistry, logger),
+		contentDir:    contentDir,
+		content:       gameContent,
 		logger:        logger,
-	}
+	}, nil
+}
+
+// SetSeed seeds the game's RNG, so the same seed generates the same level and rolls
+// Call it before Initialize
+func (g *Game) SetSeed(seed uint64) {
+	ecs.SetResource(g.world, resources.NewRNG(seed))
+}
+
+// SetDungeonAlgorithm picks how levels are generated
+func (g *Game) SetDungeonAlgorithm(algorithm dungeon.Algorithm) {
+	g.algorithm = algorithm
 }
 
-func (g *Game) Initialize() {
+// Initialize sets up a new game on the first level
+// It fails if the level can't be generated or populated, ie. because of the content
+func (g *Game) Initialize() error {
 	// Register component types
 	g.registerComponentTypes()
 
 	// Register event handlers
-	g.world.RegisterEventHandler(events.EntityDefeated, g.entityDefeatedEventHandler)
-	g.world.RegisterEventHandler(events.ItemPickedUp, g.itemPickedUpEventHandler)
-	g.world.RegisterEventHandler(events.ItemUsed, g.itemUsedEventHandler)
-	g.world.RegisterEventHandler(events.ItemEquipped, g.itemEquippedEventHandler)
-	g.world.RegisterEventHandler(events.ItemUnequipped, g.itemUnequippedEventHandler)
-	g.world.RegisterEventHandler(events.DebugStatusMessage, g.debugStatusEventHandler)
-
-	// Create player
-	player := g.entityService.SpawnPlayer(entityservice.SpawnPlayerParams{
-		X: 3, Y: 7,
-		HP: 100, MaxHP: 100,
-		Strength: 15,
-	})
-	g.turnManager.AddEntity(player)
-
-	// Create enemies
-	enemy := g.entityService.SpawnEnemy(entityservice.SpawnEnemyParams{
-		X: 15, Y: 9,
-		HP: 50, MaxHP: 50,
-		Strength: 10,
-		Sprite:   'G',
-	})
-	g.turnManager.AddEntity(enemy)
+	g.subscribeEventHandlers()
 
-	enemy2 := g.entityService.SpawnEnemy(entityservice.SpawnEnemyParams{
-		X: 19, Y: 8,
-		HP: 30, MaxHP: 30,
-		Strength: 7,
-		Sprite:   'g',
-	})
-	g.turnManager.AddEntity(enemy2)
-
-	// Create items
-	g.entityService.SpawnItem(entityservice.SpawnItemParams{
-		X: 5, Y: 5,
-		Name:   "Red Potion",
-		Weight: 1, Value: 37,
-		Sprite: 'o',
-		Effect: components.HealEffect,
-		Power:  20,
-	})
+	// Generate the first level, and start the player on it before anything else so they
+	// take the first turn
+	ecs.SetResource(g.world, &resources.Dungeon{Depth: 1, Levels: map[int]*resources.StoredLevel{}})
+	level, err := g.generateLevel(1)
+	if err != nil {
+		return fmt.Errorf("generating level: %w", err)
+	}
+	ecs.SetResource(g.world, level.Map)
+	if _, err := g.entityService.SpawnPlayer(level.Start.X, level.Start.Y); err != nil {
+		return fmt.Errorf("spawning player: %w", err)
+	}
+	if err := g.populateLevel(level, 1); err != nil {
+		return fmt.Errorf("populating level: %w", err)
+	}
 
-	g.entityService.SpawnItem(entityservice.SpawnItemParams{
-		X: 4, Y: 7,
-		Name:   "Scroll of Fireball",
-		Weight: 1, Value: 237,
-		Sprite: '~',
-		Effect: components.DamageEffect,
-		Power:  20,
-	})
+	// Look around before the first move
+	g.fovSystem.Update(g.world)
+	return nil
+}
 
-	g.entityService.SpawnWeapon(entityservice.SpawnWeaponParams{
-		X: 2, Y: 7,
-		Name:   "Rusty Sword",
-		Weight: 2, Value: 10,
-		Sprite: '|',
-		Damage: 5,
-		Slots: []components.EquipmentSlot{
-			components.RightHand,
-		},
-	})
-	g.entityService.SpawnArmor(entityservice.SpawnArmorParams{
-		X: 3, Y: 6,
-		Name:   "Leather Chestpiece",
-		Weight: 3, Value: 15,
-		Sprite:  'C',
-		Defense: 3,
-		Slots:   []components.EquipmentSlot{components.Torso},
-	})
+func (g *Game) subscribeEventHandlers() {
+	ecs.Subscribe(g.world, g.entityDefeatedEventHandler)
+	ecs.Subscribe(g.world, g.itemPickedUpEventHandler)
+	ecs.Subscribe(g.world, g.itemUsedEventHandler)
+	ecs.Subscribe(g.world, g.itemEquippedEventHandler)
+	ecs.Subscribe(g.world, g.itemUnequippedEventHandler)
+	ecs.Subscribe(g.world, g.moveBlockedEventHandler)
+	ecs.Subscribe(g.world, g.doorOpenedEventHandler)
+	ecs.Subscribe(g.world, g.debugStatusEventHandler)
+	g.subscribeActionCosts()
 }
 
 func (g *Game) registerComponentTypes() {
@@ -137,44 +208,74 @@ func (g *Game) registerComponentTypes() {
 }
 
 func (g Game) GetWidth() int {
-	return g.width
+	return g.GetTileMap().Width
 }
 
 func (g Game) GetHeight() int {
-	return g.height
+	return g.GetTileMap().Height
+}
+
+func (g Game) GetTileMap() *resources.TileMap {
+	tileMap, _ := ecs.Resource[resources.TileMap](g.world)
+	return tileMap
 }
 
 func (g Game) GetIsGameOver() bool {
-	return g.gameOver
+	return g.state().GameOver
 }
 
 func (g Game) GetStatusMessage() string {
-	return g.statusMessage
+	return g.state().StatusMessage
+}
+
+func (g *Game) SetStatusMessage(message string) {
+	g.state().StatusMessage = message
+}
+
+// state returns the GameState resource, which holds the game over flag and status message
+func (g Game) state() *resources.GameState {
+	state, _ := ecs.Resource[resources.GameState](g.world)
+	return state
 }
 
 func (g *Game) GetPlayerEntity() ecs.Entity {
-	entsWithPlayer := g.world.ComponentManager.GetAllEntitiesWithComponent(
-		components.PlayerControlled,
-	)
+	entsWithPlayer := ecs.EntitiesWith[components.PlayerControlledComponent](g.world)
 	if len(entsWithPlayer) > 0 {
 		return entsWithPlayer[0]
 	}
 	return -1
 }
 
+// PlayerView returns a check of whether the tile at x, y is in the player's view
+// The player is looked up once, so the check can be used for every tile of a frame
+func (g *Game) PlayerView() func(x, y int) bool {
+	player := g.GetPlayerEntity()
+	if player == -1 {
+		return func(x, y int) bool { return false }
+	}
+	fieldOfView, hasFieldOfView := ecs.Get[components.FieldOfViewComponent](g.world, player)
+	if !hasFieldOfView {
+		return func(x, y int) bool { return false }
+	}
+	width := g.GetWidth()
+	return func(x, y int) bool {
+		return fieldOfView.CanSee(x, y, width)
+	}
+}
+
+// GetEntities returns the entities on the current level, leaving out the ones on levels
+// the player has left
 func (g *Game) GetEntities() []ecs.Entity {
-	return g.world.EntityManager.GetAllEntities()
+	return g.world.Query(nil)
 }
 
-func (g *Game) HasComponent(entity ecs.Entity, componentType ecs.ComponentType) bool {
-	return g.world.ComponentManager.HasComponent(entity, componentType)
+// GetContentDir returns the directory the game's content was loaded from
+func (g *Game) GetContentDir() string {
+	return g.contentDir
 }
 
-func (g *Game) GetComponent(
-	entity ecs.Entity,
-	componentType ecs.ComponentType,
-) (ecs.Component, bool) {
-	return g.world.ComponentManager.GetComponent(entity, componentType)
+func (g *Game) GetWorld() *ecs.World {
+	return g.world
 }
 
 func (g *Game) GetCurrentEntity() ecs.Entity {
@@ -187,15 +288,12 @@ func (g *Game) GetPlayerInventory() *components.InventoryComponent {
 		return nil
 	}
 
-	inventoryComp, hasInventory := g.world.ComponentManager.GetComponent(
-		player,
-		components.Inventory,
-	)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](g.world, player)
 	if !hasInventory {
 		return nil
 	}
 
-	return inventoryComp.(*components.InventoryComponent)
+	return inventory
 }
 
 func (g *Game) GetPlayerUsableItems() []ecs.Entity {
@@ -206,7 +304,7 @@ func (g *Game) GetPlayerUsableItems() []ecs.Entity {
 
 	var usables []ecs.Entity
 	for _, itemEntity := range playerInventory.Items {
-		if g.world.ComponentManager.HasComponent(itemEntity, components.Usable) {
+		if ecs.Has[components.UsableComponent](g.world, itemEntity) {
 			usables = append(usables, itemEntity)
 		}
 	}
@@ -215,62 +313,16 @@ func (g *Game) GetPlayerUsableItems() []ecs.Entity {
 }
 
 // ProcessPlayerMove processes player movement input
-// Adds a MoveIntent component to the player entity if valid move
-// Adds an AttackIntent component if an enemy is at the target position
+// Adds a MoveIntent component to the player entity
+// The movement system rejects moves that are blocked, and turns moves into an enemy into an
+// attack on it
 func (g *Game) ProcessPlayerMove(dx, dy int) {
 	player := g.GetPlayerEntity()
 	if player == -1 {
 		return
 	}
 
-	// Get player position
-	posComp, hasPos := g.world.ComponentManager.GetComponent(player, components.Position)
-	if !hasPos {
-		return
-	}
-	pos := posComp.(*components.PositionComponent)
-
-	// Check for entity at target position
-	targetX, targetY := pos.X+dx, pos.Y+dy
-
-	// Boundary check
-	if targetX < 0 || targetX >= g.width || targetY < 0 || targetY >= g.height {
-		g.statusMessage = "Cannot move out of bounds"
-		return
-	}
-
-	// Check for enemy at target position (for combat)
-	entities := g.world.EntityManager.GetAllEntities()
-	for _, entity := range entities {
-		if entity == player || !g.world.EntityManager.HasEntity(entity) {
-			continue
-		}
-
-		entPosComp, hasEntPos := g.world.ComponentManager.GetComponent(entity, components.Position)
-		if !hasEntPos {
-			continue
-		}
-		entPos := entPosComp.(*components.PositionComponent)
-
-		// If entity is at target position, initiate combat
-		if entPos.X == targetX && entPos.Y == targetY {
-			if g.world.ComponentManager.HasComponent(entity, components.Health) {
-				g.world.ComponentManager.AddComponent(
-					player,
-					components.AttackIntent,
-					&components.AttackIntentComponent{Target: entity},
-				)
-				return
-			}
-		}
-	}
-
-	// If no entity at target position, move player
-	g.world.ComponentManager.AddComponent(
-		player,
-		components.MoveIntent,
-		&components.MoveIntentComponent{DX: dx, DY: dy},
-	)
+	ecs.Add(g.world, player, &components.MoveIntentComponent{DX: dx, DY: dy})
 }
 
 // ProcessPlayerPickup processes player pickup input
@@ -281,11 +333,7 @@ func (g *Game) ProcessPlayerPickup() {
 		return
 	}
 
-	g.world.ComponentManager.AddComponent(
-		player,
-		components.PickupIntent,
-		&components.PickupIntentComponent{},
-	)
+	ecs.Add(g.world, player, &components.PickupIntentComponent{})
 }
 
 // ProcessPlayerUseItem processes player use item input
@@ -297,18 +345,14 @@ func (g *Game) ProcessPlayerUseItem(itemEntity ecs.Entity) {
 	}
 
 	// Get inventory
-	inventoryComp, hasInventory := g.world.ComponentManager.GetComponent(
-		player,
-		components.Inventory,
-	)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](g.world, player)
 	if !hasInventory {
-		g.statusMessage = "No inventory found"
+		g.state().StatusMessage = "No inventory found"
 		return
 	}
 
-	inventory := inventoryComp.(*components.InventoryComponent)
 	if len(inventory.Items) == 0 {
-		g.statusMessage = "Inventory is empty"
+		g.state().StatusMessage = "Inventory is empty"
 		return
 	}
 
@@ -322,92 +366,50 @@ func (g *Game) ProcessPlayerUseItem(itemEntity ecs.Entity) {
 	}
 
 	if itemIndex == -1 {
-		g.statusMessage = "Item not found in inventory"
+		g.state().StatusMessage = "Item not found in inventory"
 		return
 	}
 
 	// Make sure item is usable
-	usableComp, hasUsable := g.world.ComponentManager.GetComponent(
-		inventory.Items[itemIndex],
-		components.Usable,
-	)
+	usable, hasUsable := ecs.Get[components.UsableComponent](g.world, inventory.Items[itemIndex])
 	if !hasUsable {
-		g.statusMessage = "Item is not usable"
+		g.state().StatusMessage = "Item is not usable"
 		return
 	}
-	usable := usableComp.(*components.UsableComponent)
 
 	// Determine the use intent based on the usable effect
 	switch usable.Effect {
 	case components.HealEffect:
-		g.world.ComponentManager.AddComponent(
-			player,
-			components.UseItemIntent,
-			&components.UseItemIntentComponent{
-				ItemEntity: inventory.Items[itemIndex],
-				Consumer:   player,
-				Target:     player,
-			},
-		)
+		ecs.Add(g.world, player, &components.UseItemIntentComponent{
+			ItemEntity: inventory.Items[itemIndex],
+			Consumer:   player,
+			Target:     player,
+		})
 	case components.DamageEffect:
-		// Get the entity closest to the player that is not the player
-		entities := g.world.EntityManager.GetAllEntities()
-
-		// Find the closest entity to the player
-		var minDist int
-		var targetEntity ecs.Entity = -1
-		for _, entity := range entities {
-			// Skip player and invalid entities
-			if entity == player || !g.world.EntityManager.HasEntity(entity) {
-				continue
-			}
-
-			// Check if entity has position and health components
-			entPosComp, hasEntPos := g.world.ComponentManager.GetComponent(
-				entity,
-				components.Position,
-			)
-			_, hasEntHealth := g.world.ComponentManager.GetComponent(entity, components.Health)
-			if !hasEntPos || !hasEntHealth {
-				continue
-			}
-			entPos := entPosComp.(*components.PositionComponent)
-
-			// Get player position
-			playerPosComp, hasPlayerPos := g.world.ComponentManager.GetComponent(
-				player,
-				components.Position,
-			)
-			if !hasPlayerPos {
-				continue
-			}
-			playerPos := playerPosComp.(*components.PositionComponent)
-
-			// Calculate distance
-			dist := mathutils.Abs(entPos.X-playerPos.X) + mathutils.Abs(entPos.Y-playerPos.Y)
-
-			// Update target entity if closer
-			if targetEntity == -1 || dist < minDist {
-				targetEntity = entity
-				minDist = dist
-			}
+		// Get player position
+		playerPos, hasPlayerPos := ecs.Get[components.PositionComponent](g.world, player)
+		if !hasPlayerPos {
+			g.state().StatusMessage = "No valid target found"
+			return
 		}
 
-		if targetEntity == -1 {
-			g.statusMessage = "No valid target found"
+		// Find the creature closest to the player that is not the player
+		targetEntity, found := g.index.Nearest(
+			playerPos.X, playerPos.Y,
+			ecs.With[components.HealthComponent](),
+			ecs.Without[components.PlayerControlledComponent](),
+		)
+		if !found {
+			g.state().StatusMessage = "No valid target found"
 			return
 		}
 
 		// Add use item intent
-		g.world.ComponentManager.AddComponent(
-			player,
-			components.UseItemIntent,
-			&components.UseItemIntentComponent{
-				ItemEntity: inventory.Items[itemIndex],
-				Consumer:   player,
-				Target:     targetEntity,
-			},
-		)
+		ecs.Add(g.world, player, &components.UseItemIntentComponent{
+			ItemEntity: inventory.Items[itemIndex],
+			Consumer:   player,
+			Target:     targetEntity,
+		})
 	case components.RepairEffect:
 	}
 }
@@ -418,25 +420,20 @@ func (g *Game) ProcessPlayerDropItem(itemEntity ecs.Entity) {
 		return
 	}
 
-	playerPosComp, _ := g.world.ComponentManager.GetComponent(
-		player,
-		components.Position,
-	)
-	playerPos := playerPosComp.(*components.PositionComponent)
+	playerPos, hasPlayerPos := ecs.Get[components.PositionComponent](g.world, player)
+	if !hasPlayerPos {
+		return
+	}
 
 	// Get inventory
-	inventoryComp, hasInventory := g.world.ComponentManager.GetComponent(
-		player,
-		components.Inventory,
-	)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](g.world, player)
 	if !hasInventory {
-		g.statusMessage = "No inventory found"
+		g.state().StatusMessage = "No inventory found"
 		return
 	}
 
-	inventory := inventoryComp.(*components.InventoryComponent)
 	if len(inventory.Items) == 0 {
-		g.statusMessage = "Inventory is empty"
+		g.state().StatusMessage = "Inventory is empty"
 		return
 	}
 
@@ -450,21 +447,19 @@ func (g *Game) ProcessPlayerDropItem(itemEntity ecs.Entity) {
 	}
 
 	if itemIndex == -1 {
-		g.statusMessage = "Item not found in inventory"
+		g.state().StatusMessage = "Item not found in inventory"
 		return
 	}
 
 	// Drop item by adding a position component to the item entity
-	g.world.ComponentManager.AddComponent(
-		itemEntity,
-		components.Position,
-		&components.PositionComponent{
-			X: playerPos.X, Y: playerPos.Y,
-		},
-	)
+	ecs.Add(g.world, itemEntity, &components.PositionComponent{
+		X: playerPos.X, Y: playerPos.Y,
+	})
 
 	// Remove item from inventory
 	inventory.Items = slices.Delete(inventory.Items, itemIndex, itemIndex+1)
+	ecs.MarkChanged[components.InventoryComponent](g.world, player)
+	ecs.RemoveParent(g.world, itemEntity)
 }
 
 func (g *Game) ProcessPlayerEquipItem(itemEntity ecs.Entity) {
@@ -474,18 +469,14 @@ func (g *Game) ProcessPlayerEquipItem(itemEntity ecs.Entity) {
 	}
 
 	// Get inventory
-	inventoryComp, hasInventory := g.world.ComponentManager.GetComponent(
-		player,
-		components.Inventory,
-	)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](g.world, player)
 	if !hasInventory {
-		g.statusMessage = "No inventory found"
+		g.state().StatusMessage = "No inventory found"
 		return
 	}
 
-	inventory := inventoryComp.(*components.InventoryComponent)
 	if len(inventory.Items) == 0 {
-		g.statusMessage = "Inventory is empty"
+		g.state().StatusMessage = "Inventory is empty"
 		return
 	}
 
@@ -499,31 +490,26 @@ func (g *Game) ProcessPlayerEquipItem(itemEntity ecs.Entity) {
 	}
 
 	if itemIndex == -1 {
-		g.statusMessage = "Item not found in inventory"
+		g.state().StatusMessage = "Item not found in inventory"
 		return
 	}
 
 	// Make sure item is equippable
-	equippableComp, hasEquippable := g.world.ComponentManager.GetComponent(
+	equippable, hasEquippable := ecs.Get[components.EquippableComponent](
+		g.world,
 		inventory.Items[itemIndex],
-		components.Equippable,
 	)
 	if !hasEquippable {
-		g.statusMessage = "Item is not equippable"
+		g.state().StatusMessage = "Item is not equippable"
 		return
 	}
-	equippable := equippableComp.(*components.EquippableComponent)
 
 	// Equip item
-	g.world.ComponentManager.AddComponent(
-		player,
-		components.EquipIntent,
-		&components.EquipIntentComponent{
-			ItemEntity: inventory.Items[itemIndex],
-			Slot:       equippable.Slots[0],
-			Target:     player,
-		},
-	)
+	ecs.Add(g.world, player, &components.EquipIntentComponent{
+		ItemEntity: inventory.Items[itemIndex],
+		Slot:       equippable.Slots[0],
+		Target:     player,
+	})
 }
 
 func (g *Game) ProcessPlayerUnequipItem(itemEntity ecs.Entity) {
@@ -533,15 +519,11 @@ func (g *Game) ProcessPlayerUnequipItem(itemEntity ecs.Entity) {
 	}
 
 	// Get equipment slots
-	inventoryComp, hasInventory := g.world.ComponentManager.GetComponent(
-		player,
-		components.Inventory,
-	)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](g.world, player)
 	if !hasInventory {
-		g.statusMessage = "No inventory found"
+		g.state().StatusMessage = "No inventory found"
 		return
 	}
-	inventory := inventoryComp.(*components.InventoryComponent)
 
 	var slot components.EquipmentSlot
 	slot = components.Undefined
@@ -553,19 +535,15 @@ func (g *Game) ProcessPlayerUnequipItem(itemEntity ecs.Entity) {
 	}
 
 	if slot == components.Undefined {
-		g.statusMessage = "Item is not equipped"
+		g.state().StatusMessage = "Item is not equipped"
 		return
 	}
 
 	// Unequip item
-	g.world.ComponentManager.AddComponent(
-		player,
-		components.UnequipIntent,
-		&components.UnequipIntentComponent{
-			Slot:   slot,
-			Target: player,
-		},
-	)
+	ecs.Add(g.world, player, &components.UnequipIntentComponent{
+		Slot:   slot,
+		Target: player,
+	})
 }
 
 // ProcessAITurn processes AI turns for all AI-controlled entities
@@ -578,7 +556,7 @@ func (g *Game) ProcessAITurn() bool {
 	}
 
 	// Skip if it's the player's turn
-	if g.world.ComponentManager.HasComponent(currentEntity, components.PlayerControlled) {
+	if ecs.Has[components.PlayerControlledComponent](g.world, currentEntity) {
 		return false
 	}
 
@@ -609,13 +587,13 @@ func (g *Game) RunAITurns() {
 		// Get current entity
 		currentEntity := g.turnManager.GetCurrentEntity()
 		if currentEntity == -1 {
-			g.gameOver = true
-			g.statusMessage = "Game Over! No entities left!"
+			g.state().GameOver = true
+			g.state().StatusMessage = "Game Over! No entities left!"
 			return
 		}
 
 		// If it's the player's turn, we're done processing AI turns
-		if g.world.ComponentManager.HasComponent(currentEntity, components.PlayerControlled) {
+		if ecs.Has[components.PlayerControlledComponent](g.world, currentEntity) {
 			return
 		}
 
@@ -627,12 +605,10 @@ func (g *Game) RunAITurns() {
 		g.world.Update()
 
 		// Check if player was defeated during this AI turn
-		playerEntities := g.world.ComponentManager.GetAllEntitiesWithComponent(
-			components.PlayerControlled,
-		)
+		playerEntities := ecs.EntitiesWith[components.PlayerControlledComponent](g.world)
 		if len(playerEntities) == 0 {
-			g.gameOver = true
-			g.statusMessage = "Game Over! You were defeated!"
+			g.state().GameOver = true
+			g.state().StatusMessage = "Game Over! You were defeated!"
 			return
 		}
 
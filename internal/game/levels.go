@@ -0,0 +1,222 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+
+	"ecs/internal/game/components"
+	"ecs/internal/game/dungeon"
+	"ecs/internal/game/prefabs"
+	"ecs/internal/game/resources"
+	"ecs/pkg/ecs"
+	"ecs/pkg/mathutils"
+)
+
+// Size of generated levels
+const (
+	levelWidth  = 50
+	levelHeight = 20
+)
+
+// GetDepth returns how deep the level the player is on is, starting at 1
+func (g *Game) GetDepth() int {
+	return g.levels().Depth
+}
+
+// levels returns the Dungeon resource, which keeps the levels the player has left
+func (g Game) levels() *resources.Dungeon {
+	levels, _ := ecs.Resource[resources.Dungeon](g.world)
+	return levels
+}
+
+// generateLevel creates a new level at the depth, without making it the current tile map
+// The level is generated from the game's RNG, so it can be replayed from the seed
+func (g *Game) generateLevel(depth int) (*dungeon.Level, error) {
+	rng, _ := ecs.Resource[resources.RNG](g.world)
+	algorithm := g.algorithm
+	if algorithm == "" {
+		algorithm = dungeon.Algorithms[rng.IntN(len(dungeon.Algorithms))]
+	}
+
+	level, err := dungeon.Generate(algorithm, levelWidth, levelHeight, rng.Rand)
+	if err != nil {
+		return nil, err
+	}
+	level.AddStairs(depth > 1)
+	return level, nil
+}
+
+// populateLevel spawns the enemies and items of a new level
+// Deeper levels have more enemies, and tougher ones as content says they can be found there
+func (g *Game) populateLevel(level *dungeon.Level, depth int) error {
+	rng, _ := ecs.Resource[resources.RNG](g.world)
+	population := dungeon.Population{
+		Enemies:      4 + 2*depth,
+		Items:        6,
+		EnemyPrefabs: g.content.CreatureNames(depth),
+		// The player's sword isn't found lying around
+		ItemPrefabs: slices.DeleteFunc(g.content.ItemNames(), func(name string) bool {
+			return name == prefabs.StartingSword
+		}),
+	}
+	return dungeon.Populate(level, g.entityService, population, rng.Rand)
+}
+
+// ProcessPlayerStairs takes the player down or up the stairs they are standing on
+func (g *Game) ProcessPlayerStairs(down bool) {
+	player := g.GetPlayerEntity()
+	if player == -1 {
+		return
+	}
+	pos, hasPos := ecs.Get[components.PositionComponent](g.world, player)
+	if !hasPos {
+		return
+	}
+
+	stairs, depth, direction := resources.StairsUp, g.GetDepth()-1, "up"
+	if down {
+		stairs, depth, direction = resources.StairsDown, g.GetDepth()+1, "down"
+	}
+	if g.GetTileMap().At(pos.X, pos.Y) != stairs {
+		g.state().StatusMessage = fmt.Sprintf("There are no stairs %s here", direction)
+		return
+	}
+
+	if err := g.changeLevel(player, depth); err != nil {
+		g.state().StatusMessage = fmt.Sprintf("Could not take the stairs: %v", err)
+		return
+	}
+	g.state().StatusMessage = fmt.Sprintf("You take the stairs %s to depth %d", direction, depth)
+}
+
+// changeLevel moves the player, and everything they carry, to the level at the depth
+// The level they leave is kept with its entities disabled, and is restored as it was when
+// they come back. Levels they haven't been to yet are generated
+// If the new level can't be set up, the player stays where they were
+func (g *Game) changeLevel(player ecs.Entity, depth int) error {
+	levels := g.levels()
+
+	// Generate the level before leaving this one, so there is nothing to undo if it fails
+	stored, visited := levels.Levels[depth]
+	var level *dungeon.Level
+	if !visited {
+		generated, err := g.generateLevel(depth)
+		if err != nil {
+			return err
+		}
+		level = generated
+	}
+
+	// Arrive on the stairs leading back to the level that was left
+	arrival := resources.StairsUp
+	if depth < levels.Depth {
+		arrival = resources.StairsDown
+	}
+	from, _ := ecs.Get[components.PositionComponent](g.world, player)
+	fromX, fromY, fromDepth := from.X, from.Y, levels.Depth
+
+	left := g.leaveLevel(player)
+	levels.Levels[fromDepth] = left
+	levels.Depth = depth
+
+	if visited {
+		delete(levels.Levels, depth)
+		g.restoreLevel(stored)
+		x, y, _ := stored.Map.Find(arrival)
+		x, y = g.freeTileNear(x, y)
+		g.movePlayer(player, x, y)
+		g.arrive(player)
+		return nil
+	}
+
+	ecs.SetResource(g.world, level.Map)
+	g.movePlayer(player, level.Start.X, level.Start.Y)
+	if err := g.populateLevel(level, depth); err != nil {
+		// Go back to the level that was left, without anything spawned on the new one
+		travellers := append([]ecs.Entity{player}, ecs.Descendants(g.world, player)...)
+		for _, entity := range g.world.Query(nil) {
+			if !slices.Contains(travellers, entity) {
+				g.world.RemoveEntity(entity)
+			}
+		}
+		delete(levels.Levels, fromDepth)
+		levels.Depth = fromDepth
+		g.restoreLevel(left)
+		g.movePlayer(player, fromX, fromY)
+		g.arrive(player)
+		return err
+	}
+	g.arrive(player)
+	return nil
+}
+
+// leaveLevel disables everything on the current level but the player and what they carry,
+// and returns it to be stored
+func (g *Game) leaveLevel(player ecs.Entity) *resources.StoredLevel {
+	travellers := append([]ecs.Entity{player}, ecs.Descendants(g.world, player)...)
+	left := &resources.StoredLevel{Map: g.GetTileMap()}
+	for _, entity := range g.world.Query(nil) {
+		if !slices.Contains(travellers, entity) {
+			ecs.Disable(g.world, entity)
+			left.Entities = append(left.Entities, entity)
+		}
+	}
+	return left
+}
+
+// restoreLevel makes the stored level the current one, enabling its entities again
+func (g *Game) restoreLevel(stored *resources.StoredLevel) {
+	ecs.SetResource(g.world, stored.Map)
+	for _, entity := range stored.Entities {
+		if g.world.IsAlive(entity) {
+			ecs.Enable(g.world, entity)
+		}
+	}
+}
+
+// freeTileNear returns the walkable tile nearest the one given that no other creature is
+// standing on, so the player doesn't arrive on top of one waiting by the stairs
+// Ties are broken in map order, and the tile itself is returned if nothing is free
+func (g *Game) freeTileNear(x, y int) (int, int) {
+	tileMap := g.GetTileMap()
+	distances := dungeon.Distances(tileMap, mathutils.Point{X: x, Y: y})
+
+	best, bestDistance := mathutils.Point{X: x, Y: y}, -1
+	for ty := range tileMap.Height {
+		for tx := range tileMap.Width {
+			distance, reachable := distances.Distance(tx, ty)
+			if !reachable || !tileMap.Walkable(tx, ty) ||
+				(bestDistance != -1 && distance >= bestDistance) {
+				continue
+			}
+			blockers := g.index.EntitiesAt(
+				tx, ty,
+				ecs.With[components.BlockerComponent](),
+				ecs.Without[components.PlayerControlledComponent](),
+			)
+			if len(blockers) == 0 {
+				best, bestDistance = mathutils.Point{X: tx, Y: ty}, distance
+			}
+		}
+	}
+	return best.X, best.Y
+}
+
+// arrive sets up the turn order for the level the player just arrived on, and looks around it
+func (g *Game) arrive(player ecs.Entity) {
+	g.rebuildTurnOrder(player)
+	g.fovSystem.Update(g.world)
+}
+
+// movePlayer puts the player on the tile, replacing their position so the spatial index
+// picks it up straight away
+func (g *Game) movePlayer(player ecs.Entity, x, y int) {
+	ecs.Add(g.world, player, &components.PositionComponent{X: x, Y: y})
+}
+
+// rebuildTurnOrder makes every creature on the current level take turns, starting with
+// the player
+func (g *Game) rebuildTurnOrder(player ecs.Entity) {
+	creatures := ecs.EntitiesWith[components.HealthComponent](g.world)
+	g.turnManager.SetTurnOrder(creatures, slices.Index(creatures, player))
+}
@@ -0,0 +1,45 @@
+package prefabs
+
+import (
+	"ecs/internal/game/components"
+	"ecs/pkg/ecs"
+)
+
+// Prefab names used by the game itself
+// The player is built in, everything else comes from the content files
+const (
+	Player        = "player"
+	StartingSword = "starting_sword"
+)
+
+// Register adds the built-in prefabs to the registry
+func Register(registry *ecs.PrefabRegistry) error {
+	for _, prefab := range builtin {
+		if err := registry.Register(prefab); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var builtin = []ecs.Prefab{
+	{
+		Name: Player,
+		Components: []func() ecs.Component{
+			func() ecs.Component { return &components.HealthComponent{HP: 100, MaxHP: 100} },
+			func() ecs.Component { return &components.StrengthComponent{Strength: 15} },
+			func() ecs.Component { return &components.SpriteComponent{Char: '@'} },
+			func() ecs.Component { return &components.SpeedComponent{Speed: components.NormalSpeed} },
+			func() ecs.Component { return &components.PlayerControlledComponent{} },
+			func() ecs.Component { return &components.BlockerComponent{} },
+			func() ecs.Component { return &components.FieldOfViewComponent{Radius: 8} },
+			func() ecs.Component {
+				return &components.InventoryComponent{
+					Items:       []ecs.Entity{},
+					Slots:       map[components.EquipmentSlot]ecs.Entity{},
+					MaxCapacity: 30,
+				}
+			},
+		},
+	},
+}
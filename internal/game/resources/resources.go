@@ -0,0 +1,52 @@
+package resources
+
+import (
+	"math/rand/v2"
+
+	"ecs/pkg/ecs"
+)
+
+// GameState stores the state of the current run
+type GameState struct {
+	GameOver      bool
+	StatusMessage string
+}
+
+// RNG is the random number generator shared by all game rules
+// Its state is saved with the world, so a loaded game rolls the same numbers
+type RNG struct {
+	*rand.Rand
+	source *rand.PCG
+}
+
+// NewRNG creates a random number generator with a fixed seed, so runs can be replayed
+func NewRNG(seed uint64) *RNG {
+	source := rand.NewPCG(seed, seed)
+	return &RNG{Rand: rand.New(source), source: source}
+}
+
+func (r *RNG) GobEncode() ([]byte, error) {
+	return r.source.MarshalBinary()
+}
+
+func (r *RNG) GobDecode(data []byte) error {
+	source := &rand.PCG{}
+	if err := source.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	r.Rand, r.source = rand.New(source), source
+	return nil
+}
+
+// Dungeon tracks how deep the player is, and keeps the levels they have left so they
+// are just as they were when the player comes back
+type Dungeon struct {
+	Depth  int // The first level is depth 1
+	Levels map[int]*StoredLevel
+}
+
+// StoredLevel is a level the player isn't on
+type StoredLevel struct {
+	Map      *TileMap
+	Entities []ecs.Entity // Disabled until the player comes back
+}
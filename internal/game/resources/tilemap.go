@@ -0,0 +1,190 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tile is the terrain of a single map cell
+type Tile uint8
+
+const (
+	Floor Tile = iota
+	Wall
+	Door
+	Water
+	Grass
+	StairsDown
+	StairsUp
+	ClosedDoor
+)
+
+type tileInfo struct {
+	name        string
+	glyph       rune
+	walkable    bool
+	blocksSight bool
+}
+
+var tiles = map[Tile]tileInfo{
+	Floor:      {name: "floor", glyph: '·', walkable: true},
+	Wall:       {name: "wall", glyph: '#', walkable: false, blocksSight: true},
+	Door:       {name: "open door", glyph: '\'', walkable: true},
+	Water:      {name: "water", glyph: '≈', walkable: false},
+	Grass:      {name: "grass", glyph: '"', walkable: true},
+	StairsDown: {name: "stairs down", glyph: '>', walkable: true},
+	StairsUp:   {name: "stairs up", glyph: '<', walkable: true},
+	ClosedDoor: {name: "closed door", glyph: '+', walkable: false, blocksSight: true},
+}
+
+func (t Tile) String() string {
+	return tiles[t].name
+}
+
+// Glyph returns the character the tile is drawn with
+func (t Tile) Glyph() rune {
+	return tiles[t].glyph
+}
+
+// Walkable reports whether creatures can move onto the tile
+func (t Tile) Walkable() bool {
+	return tiles[t].walkable
+}
+
+// Passable reports whether creatures can get onto the tile, either by walking onto it or by
+// opening it first, ie. a closed door
+func (t Tile) Passable() bool {
+	return t.Walkable() || t == ClosedDoor
+}
+
+// BlocksSight reports whether the tile hides what is behind it
+func (t Tile) BlocksSight() bool {
+	return tiles[t].blocksSight
+}
+
+// TileMap stores the terrain of the current level, row by row
+type TileMap struct {
+	Width  int
+	Height int
+	Tiles  []Tile
+
+	// Explored marks the tiles the player has seen, in the same order as Tiles
+	Explored []bool
+}
+
+// NewTileMap creates a map of the given size, filled with the tile
+func NewTileMap(width, height int, fill Tile) *TileMap {
+	tileMap := &TileMap{
+		Width:    width,
+		Height:   height,
+		Tiles:    make([]Tile, width*height),
+		Explored: make([]bool, width*height),
+	}
+	for i := range tileMap.Tiles {
+		tileMap.Tiles[i] = fill
+	}
+	return tileMap
+}
+
+// ParseTileMap creates a map from rows of tile glyphs, ie. "#·+≈"
+// Every row must be the same length
+func ParseTileMap(rows []string) (*TileMap, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("tile map has no rows")
+	}
+
+	byGlyph := map[rune]Tile{}
+	for tile, info := range tiles {
+		byGlyph[info.glyph] = tile
+	}
+
+	width := len([]rune(rows[0]))
+	tileMap := NewTileMap(width, len(rows), Floor)
+	for y, row := range rows {
+		glyphs := []rune(row)
+		if len(glyphs) != width {
+			return nil, fmt.Errorf("tile map row %d is %d tiles wide, expected %d", y, len(glyphs), width)
+		}
+		for x, glyph := range glyphs {
+			tile, found := byGlyph[glyph]
+			if !found {
+				return nil, fmt.Errorf("tile map row %d has unknown tile %q at column %d", y, glyph, x)
+			}
+			tileMap.Set(x, y, tile)
+		}
+	}
+	return tileMap, nil
+}
+
+// InBounds reports whether the tile at x, y is on the map
+func (m *TileMap) InBounds(x, y int) bool {
+	return x >= 0 && x < m.Width && y >= 0 && y < m.Height
+}
+
+// At returns the tile at x, y, treating anything off the map as wall
+func (m *TileMap) At(x, y int) Tile {
+	if !m.InBounds(x, y) {
+		return Wall
+	}
+	return m.Tiles[y*m.Width+x]
+}
+
+// Set changes the tile at x, y, ignoring positions off the map
+func (m *TileMap) Set(x, y int, tile Tile) {
+	if m.InBounds(x, y) {
+		m.Tiles[y*m.Width+x] = tile
+	}
+}
+
+// Walkable reports whether creatures can move onto the tile at x, y
+func (m *TileMap) Walkable(x, y int) bool {
+	return m.InBounds(x, y) && m.At(x, y).Walkable()
+}
+
+// Passable reports whether creatures can get onto the tile at x, y, opening it if they must
+func (m *TileMap) Passable(x, y int) bool {
+	return m.InBounds(x, y) && m.At(x, y).Passable()
+}
+
+// BlocksSight reports whether the tile at x, y hides what is behind it,
+// treating anything off the map as wall
+func (m *TileMap) BlocksSight(x, y int) bool {
+	return m.At(x, y).BlocksSight()
+}
+
+// IsExplored reports whether the player has seen the tile at x, y
+func (m *TileMap) IsExplored(x, y int) bool {
+	return m.InBounds(x, y) && y*m.Width+x < len(m.Explored) && m.Explored[y*m.Width+x]
+}
+
+// Explore marks the tile at x, y as seen by the player, ignoring positions off the map
+func (m *TileMap) Explore(x, y int) {
+	if !m.InBounds(x, y) {
+		return
+	}
+	m.Explored[y*m.Width+x] = true
+}
+
+// Find returns the position of the first tile of the kind, scanning row by row
+func (m *TileMap) Find(tile Tile) (int, int, bool) {
+	for y := range m.Height {
+		for x := range m.Width {
+			if m.At(x, y) == tile {
+				return x, y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// String draws the map with one row of glyphs per line
+func (m *TileMap) String() string {
+	var b strings.Builder
+	for y := range m.Height {
+		for x := range m.Width {
+			b.WriteRune(m.At(x, y).Glyph())
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
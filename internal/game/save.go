@@ -0,0 +1,101 @@
+package game
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"ecs/pkg/ecs"
+)
+
+// SavePath is where the TUI saves the current run, and where "continue" loads it from
+const SavePath = "savegame.bin"
+
+// saveVersion is bumped whenever the layout of saveFile or the saved resources change
+const saveVersion = 6
+
+// saveFile is everything needed to resume a run
+// The tile map, game over flag and status message are resources, so they are part of the world
+type saveFile struct {
+	Version     int
+	World       []byte // Binary world snapshot
+	TurnOrder   []ecs.Entity
+	CurrentTurn int
+	Energy      map[ecs.Entity]int
+}
+
+// Save writes the world, the turn order and everyone's energy to the writer
+func (g *Game) Save(writer io.Writer) error {
+	var world bytes.Buffer
+	if err := g.world.SaveBinary(&world); err != nil {
+		return fmt.Errorf("saving world: %w", err)
+	}
+
+	turnOrder, currentTurn := g.turnManager.TurnOrder()
+	return gob.NewEncoder(writer).Encode(saveFile{
+		Version:     saveVersion,
+		World:       world.Bytes(),
+		TurnOrder:   turnOrder,
+		CurrentTurn: currentTurn,
+		Energy:      g.turnManager.Energy(),
+	})
+}
+
+// Load creates a game from one written by Save, ready to be played without Initialize
+// The content the game was saved with should be loaded from the content directory
+func Load(reader io.Reader, logger *log.Logger, contentDir string) (*Game, error) {
+	var save saveFile
+	if err := gob.NewDecoder(reader).Decode(&save); err != nil {
+		return nil, fmt.Errorf("reading save: %w", err)
+	}
+	if save.Version != saveVersion {
+		return nil, fmt.Errorf("unsupported save version %d", save.Version)
+	}
+
+	g, err := NewGame(logger, contentDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.world.LoadBinary(bytes.NewReader(save.World)); err != nil {
+		return nil, fmt.Errorf("loading world: %w", err)
+	}
+	g.subscribeEventHandlers()
+
+	// Loading the world re-adds every creature to the turn order, so put it back as it was
+	g.turnManager.SetTurnOrder(save.TurnOrder, save.CurrentTurn)
+	g.turnManager.SetEnergy(save.Energy)
+
+	return g, nil
+}
+
+// SaveGame saves the game to the file at path, and reports how it went in the status message
+func (g *Game) SaveGame(path string) {
+	if err := g.SaveToFile(path); err != nil {
+		g.state().StatusMessage = fmt.Sprintf("Could not save game: %v", err)
+		return
+	}
+	g.state().StatusMessage = "Game saved"
+}
+
+// SaveToFile saves the game to the file at path, replacing it if it exists
+func (g *Game) SaveToFile(path string) error {
+	var save bytes.Buffer
+	if err := g.Save(&save); err != nil {
+		return err
+	}
+	return os.WriteFile(path, save.Bytes(), 0o644)
+}
+
+// LoadFromFile loads a game saved to the file at path
+func LoadFromFile(path string, logger *log.Logger, contentDir string) (*Game, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return Load(file, logger, contentDir)
+}
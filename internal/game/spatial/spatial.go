@@ -0,0 +1,189 @@
+package spatial
+
+import (
+	"cmp"
+	"slices"
+
+	"ecs/internal/game/components"
+	"ecs/pkg/ecs"
+	"ecs/pkg/mathutils"
+)
+
+// Entities are bucketed by square cells of this many tiles, so radius lookups only look at
+// the cells around the tile
+const cellSize = 8
+
+// Index buckets entities by the tile their Position is on, so what is at or near a tile
+// can be looked up without scanning every entity
+// Adding, replacing and removing a Position keeps it in sync through hooks. Positions
+// changed in place are picked up by Update, from being marked changed
+// Disabled entities are left out, like they are from queries
+type Index struct {
+	world     *ecs.World
+	cells     map[mathutils.Point][]ecs.Entity
+	positions map[ecs.Entity]mathutils.Point
+}
+
+// NewIndex creates an index of the world's positions, and keeps it up to date
+func NewIndex(world *ecs.World) *Index {
+	index := &Index{
+		world:     world,
+		cells:     map[mathutils.Point][]ecs.Entity{},
+		positions: map[ecs.Entity]mathutils.Point{},
+	}
+
+	ecs.OnAdd(world, func(entity ecs.Entity, pos *components.PositionComponent) {
+		if !ecs.IsDisabled(world, entity) {
+			index.move(entity, mathutils.Point{X: pos.X, Y: pos.Y})
+		}
+	})
+	ecs.OnReplace(world, func(entity ecs.Entity, _, pos *components.PositionComponent) {
+		if !ecs.IsDisabled(world, entity) {
+			index.move(entity, mathutils.Point{X: pos.X, Y: pos.Y})
+		}
+	})
+	ecs.OnRemove(world, func(entity ecs.Entity, _ *components.PositionComponent) {
+		index.remove(entity)
+	})
+
+	ecs.OnAdd(world, func(entity ecs.Entity, _ *ecs.Disabled) {
+		index.remove(entity)
+	})
+	ecs.OnRemove(world, func(entity ecs.Entity, _ *ecs.Disabled) {
+		if pos, hasPos := ecs.Get[components.PositionComponent](world, entity); hasPos {
+			index.move(entity, mathutils.Point{X: pos.X, Y: pos.Y})
+		}
+	})
+
+	for _, positioned := range ecs.Query1[components.PositionComponent](world) {
+		index.move(positioned.Entity, mathutils.Point{X: positioned.A.X, Y: positioned.A.Y})
+	}
+	return index
+}
+
+// Update moves the entities whose Position changed since it last ran
+// Run as a system, after anything that moves entities; called outside the schedule it
+// re-checks every position
+func (i *Index) Update(world *ecs.World) {
+	moved := ecs.Query1[components.PositionComponent](
+		world,
+		ecs.Changed[components.PositionComponent](),
+	)
+	for _, positioned := range moved {
+		i.move(positioned.Entity, mathutils.Point{X: positioned.A.X, Y: positioned.A.Y})
+	}
+}
+
+// EntitiesAt returns the entities on the tile that pass every filter, lowest entity first
+func (i *Index) EntitiesAt(x, y int, filters ...ecs.Filter) []ecs.Entity {
+	p := mathutils.Point{X: x, Y: y}
+	entities := []ecs.Entity{}
+	for _, entity := range i.cells[cellOf(p)] {
+		if i.positions[entity] == p && i.passes(entity, filters) {
+			entities = append(entities, entity)
+		}
+	}
+	slices.Sort(entities)
+	return entities
+}
+
+// EntitiesInRadius returns the entities within radius steps of the tile (ie. by Manhattan
+// distance) that pass every filter, nearest first
+func (i *Index) EntitiesInRadius(x, y, radius int, filters ...ecs.Filter) []ecs.Entity {
+	center := mathutils.Point{X: x, Y: y}
+	from := cellOf(mathutils.Point{X: x - radius, Y: y - radius})
+	to := cellOf(mathutils.Point{X: x + radius, Y: y + radius})
+
+	entities := []ecs.Entity{}
+	for cellY := from.Y; cellY <= to.Y; cellY++ {
+		for cellX := from.X; cellX <= to.X; cellX++ {
+			for _, entity := range i.cells[mathutils.Point{X: cellX, Y: cellY}] {
+				if distance(i.positions[entity], center) <= radius && i.passes(entity, filters) {
+					entities = append(entities, entity)
+				}
+			}
+		}
+	}
+
+	// Nearest first, then by entity so ties always come out the same way
+	slices.SortFunc(entities, func(a, b ecs.Entity) int {
+		return cmp.Or(
+			cmp.Compare(distance(i.positions[a], center), distance(i.positions[b], center)),
+			cmp.Compare(a, b),
+		)
+	})
+	return entities
+}
+
+// Nearest returns the entity nearest the tile that passes every filter
+// Ties are broken by the lowest entity
+func (i *Index) Nearest(x, y int, filters ...ecs.Filter) (ecs.Entity, bool) {
+	// Look further out until something passes, or every entity has been looked at
+	for radius := cellSize; ; radius *= 2 {
+		candidates := i.EntitiesInRadius(x, y, radius)
+		for _, entity := range candidates {
+			if i.passes(entity, filters) {
+				return entity, true
+			}
+		}
+		if len(candidates) == len(i.positions) {
+			return -1, false
+		}
+	}
+}
+
+func (i *Index) passes(entity ecs.Entity, filters []ecs.Filter) bool {
+	for _, filter := range filters {
+		if !filter(i.world, entity) {
+			return false
+		}
+	}
+	return true
+}
+
+// move puts the entity in the cell of its new position, taking it out of its old one
+func (i *Index) move(entity ecs.Entity, p mathutils.Point) {
+	old, indexed := i.positions[entity]
+	if indexed && old == p {
+		return
+	}
+	if indexed && cellOf(old) != cellOf(p) {
+		i.removeFromCell(entity, cellOf(old))
+	}
+	if !indexed || cellOf(old) != cellOf(p) {
+		i.cells[cellOf(p)] = append(i.cells[cellOf(p)], entity)
+	}
+	i.positions[entity] = p
+}
+
+func (i *Index) remove(entity ecs.Entity) {
+	if p, indexed := i.positions[entity]; indexed {
+		i.removeFromCell(entity, cellOf(p))
+		delete(i.positions, entity)
+	}
+}
+
+func (i *Index) removeFromCell(entity ecs.Entity, cell mathutils.Point) {
+	i.cells[cell] = slices.DeleteFunc(i.cells[cell], func(e ecs.Entity) bool {
+		return e == entity
+	})
+	if len(i.cells[cell]) == 0 {
+		delete(i.cells, cell)
+	}
+}
+
+// cellOf returns the cell the tile is bucketed in, rounding down for negative positions
+func cellOf(p mathutils.Point) mathutils.Point {
+	return mathutils.Point{X: floorDiv(p.X, cellSize), Y: floorDiv(p.Y, cellSize)}
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+func distance(a, b mathutils.Point) int {
+	return mathutils.Abs(a.X-b.X) + mathutils.Abs(a.Y-b.Y)
+}
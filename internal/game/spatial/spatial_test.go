@@ -0,0 +1,123 @@
+package spatial
+
+import (
+	"io"
+	"log"
+	"slices"
+	"testing"
+
+	"ecs/internal/game/components"
+	"ecs/pkg/ecs"
+)
+
+func newWorld() *ecs.World {
+	return ecs.NewWorld(log.New(io.Discard, "", 0))
+}
+
+func spawnAt(world *ecs.World, x, y int, extra ...ecs.Component) ecs.Entity {
+	entity := world.EntityManager.CreateEntity()
+	ecs.Add(world, entity, &components.PositionComponent{X: x, Y: y})
+	for _, component := range extra {
+		world.ComponentManager.AddComponent(entity, ecs.TypeOfComponent(component), component)
+	}
+	return entity
+}
+
+func expectAt(t *testing.T, index *Index, x, y int, want ...ecs.Entity) {
+	t.Helper()
+	if got := index.EntitiesAt(x, y); !slices.Equal(got, want) {
+		t.Fatalf("entities at %d, %d = %v, want %v", x, y, got, want)
+	}
+}
+
+func TestIndexFollowsPositions(t *testing.T) {
+	world := newWorld()
+	before := spawnAt(world, 2, 2)
+	index := NewIndex(world)
+	after := spawnAt(world, 2, 2)
+	expectAt(t, index, 2, 2, before, after)
+
+	// Moved in place, and picked up by Update, to a tile in another cell
+	pos, _ := ecs.Get[components.PositionComponent](world, before)
+	pos.X, pos.Y = 20, 9
+	ecs.MarkChanged[components.PositionComponent](world, before)
+	index.Update(world)
+	expectAt(t, index, 2, 2, after)
+	expectAt(t, index, 20, 9, before)
+
+	// Moved by replacing the position, within the same cell
+	ecs.Add(world, after, &components.PositionComponent{X: 3, Y: 2})
+	expectAt(t, index, 2, 2)
+	expectAt(t, index, 3, 2, after)
+
+	// Despawned, and by losing the position
+	world.RemoveEntity(before)
+	expectAt(t, index, 20, 9)
+	ecs.Remove[components.PositionComponent](world, after)
+	expectAt(t, index, 3, 2)
+	if nearest, found := index.Nearest(0, 0); found {
+		t.Fatalf("nearest = %v, want nothing left in the index", nearest)
+	}
+}
+
+func TestIndexLeavesOutDisabledEntities(t *testing.T) {
+	world := newWorld()
+	index := NewIndex(world)
+	entity := spawnAt(world, 4, 4)
+
+	ecs.Disable(world, entity)
+	expectAt(t, index, 4, 4)
+
+	// Moving while disabled is picked up when it is enabled again
+	ecs.Add(world, entity, &components.PositionComponent{X: 30, Y: 1})
+	expectAt(t, index, 30, 1)
+	ecs.Enable(world, entity)
+	expectAt(t, index, 4, 4)
+	expectAt(t, index, 30, 1, entity)
+}
+
+func TestEntitiesInRadius(t *testing.T) {
+	world := newWorld()
+	corner := spawnAt(world, 0, 0)
+	near := spawnAt(world, 1, 1, &components.BlockerComponent{})
+	edge := spawnAt(world, 0, 3, &components.BlockerComponent{})
+	spawnAt(world, 3, 1) // 4 steps away
+	spawnAt(world, 49, 19)
+	index := NewIndex(world)
+
+	// The radius reaches off the map, into cells with negative positions
+	want := []ecs.Entity{corner, near, edge}
+	if got := index.EntitiesInRadius(0, 0, 3); !slices.Equal(got, want) {
+		t.Fatalf("in radius = %v, want %v", got, want)
+	}
+	blockers := index.EntitiesInRadius(0, 0, 3, ecs.With[components.BlockerComponent]())
+	if want := []ecs.Entity{near, edge}; !slices.Equal(blockers, want) {
+		t.Fatalf("blockers in radius = %v, want %v", blockers, want)
+	}
+	if got := index.EntitiesInRadius(-5, -5, 2); len(got) != 0 {
+		t.Fatalf("in radius off the map = %v, want none", got)
+	}
+}
+
+func TestNearest(t *testing.T) {
+	world := newWorld()
+	spawnAt(world, 0, 0)
+	first := spawnAt(world, 48, 19, &components.BlockerComponent{})
+	second := spawnAt(world, 49, 18, &components.BlockerComponent{})
+	index := NewIndex(world)
+
+	// The only blockers are in the opposite corner, further than the first radius looked at
+	nearest, found := index.Nearest(0, 0, ecs.With[components.BlockerComponent]())
+	if !found || nearest != first {
+		t.Fatalf("nearest blocker = %v, want %v", nearest, first)
+	}
+
+	// Both are a step from the corner, so the lowest entity wins
+	nearest, found = index.Nearest(49, 19, ecs.With[components.BlockerComponent]())
+	if !found || nearest != first {
+		t.Fatalf("nearest blocker to the corner = %v, want %v before %v", nearest, first, second)
+	}
+	if nearest, found := index.Nearest(0, 0, ecs.With[components.HostileComponent]()); found {
+		t.Fatalf("nearest hostile = %v, want none", nearest)
+	}
+}
@@ -2,93 +2,128 @@ package systems
 
 import (
 	"ecs/internal/game/components"
+	"ecs/internal/game/resources"
 	"ecs/pkg/ecs"
 	"ecs/pkg/mathutils"
+	"ecs/pkg/pathfinding"
 )
 
+// What it costs to path through a closed door, which takes a turn to open first
+const closedDoorCost = 2
+
 // Currently the AI system is very simple, and has two behaviors
 // 1. If the AI entity is adjacent to a player-controlled entity, it will attack
-// 2. If the AI entity is not adjacent to a player-controlled entity, it will move toward the player
+// 2. If the AI entity is not adjacent to a player-controlled entity, it will move toward the
+// player, one step at a time in the four cardinal directions along the cheapest path
 type AISystem struct {
 	CurrentEntity ecs.Entity
+
+	// The cheapest way to the player from every tile, shared by every creature until the
+	// player moves or the level changes
+	towardPlayer *pathfinding.DijkstraMap
+	mapped       *resources.TileMap
+	mappedPlayer mathutils.Point
 }
 
 func (ai *AISystem) Update(world *ecs.World) {
-	if !world.EntityManager.HasEntity(ai.CurrentEntity) {
+	if !world.IsAlive(ai.CurrentEntity) {
 		return
 	}
 
 	// Skip if it's a player-controlled entity
-	if world.ComponentManager.HasComponent(ai.CurrentEntity, components.PlayerControlled) {
+	if ecs.Has[components.PlayerControlledComponent](world, ai.CurrentEntity) {
 		return
 	}
 
-	// Only process if entity has health (is alive)
-	if _, hasHealth := world.ComponentManager.GetComponent(ai.CurrentEntity, components.Health); !hasHealth {
+	// Only process if entity has health (is alive) and a position
+	if !ecs.Has[components.HealthComponent](world, ai.CurrentEntity) {
+		return
+	}
+	pos, hasPos := ecs.Get[components.PositionComponent](world, ai.CurrentEntity)
+	if !hasPos {
 		return
 	}
 
 	// Find a player-controlled entity to attack
-	var target ecs.Entity
-	playerEntities := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.PlayerControlled,
+	targets := ecs.Query1[components.PositionComponent](
+		world,
+		ecs.With[components.PlayerControlledComponent](),
 	)
-	if len(playerEntities) == 0 {
+	if len(targets) == 0 {
 		return
 	}
-	target = playerEntities[0]
+	target := targets[0]
 
 	// Check if adjacent to target
-	if ai.isAdjacent(world, ai.CurrentEntity, target) {
+	if mathutils.Adjacent(pos.X, pos.Y, target.A.X, target.A.Y) {
 		// Attack if adjacent
-		world.ComponentManager.AddComponent(
-			ai.CurrentEntity,
-			components.AttackIntent,
-			&components.AttackIntentComponent{Target: target},
-		)
+		ecs.Add(world, ai.CurrentEntity, &components.AttackIntentComponent{Target: target.Entity})
 	} else {
 		// Move toward target
-		ai.moveToward(world, ai.CurrentEntity, target)
+		ai.moveToward(world, ai.CurrentEntity, pos, target.A)
 	}
 }
 
-func (ai *AISystem) isAdjacent(world *ecs.World, e1, e2 ecs.Entity) bool {
-	pos1Comp, found1 := world.ComponentManager.GetComponent(e1, components.Position)
-	pos2Comp, found2 := world.ComponentManager.GetComponent(e2, components.Position)
+// moveToward takes one step along the cheapest path to the target, around walls
+// Creatures in the way aren't walked through, so a creature steps around one if that still
+// gets it closer, and otherwise waits for it to move
+func (ai *AISystem) moveToward(
+	world *ecs.World,
+	entity ecs.Entity,
+	pos1, pos2 *components.PositionComponent,
+) {
+	tileMap, hasTileMap := ecs.Resource[resources.TileMap](world)
+	if !hasTileMap {
+		return
+	}
 
-	if !found1 || !found2 {
-		return false
+	occupied := map[mathutils.Point]bool{}
+	creatures := ecs.Query1[components.PositionComponent](
+		world,
+		ecs.With[components.BlockerComponent](),
+	)
+	for _, creature := range creatures {
+		occupied[mathutils.Point{X: creature.A.X, Y: creature.A.Y}] = true
 	}
 
-	pos1 := pos1Comp.(*components.PositionComponent)
-	pos2 := pos2Comp.(*components.PositionComponent)
+	towardPlayer := ai.mapToward(tileMap, mathutils.Point{X: pos2.X, Y: pos2.Y})
+	step, found := towardPlayer.Downhill(pos1.X, pos1.Y, func(x, y int) bool {
+		return occupied[mathutils.Point{X: x, Y: y}]
+	})
+	if !found {
+		return
+	}
 
-	return mathutils.Adjacent(pos1.X, pos1.Y, pos2.X, pos2.Y)
+	ecs.Add(world, entity, &components.MoveIntentComponent{
+		DX: step.X - pos1.X,
+		DY: step.Y - pos1.Y,
+	})
 }
 
-func (ai *AISystem) moveToward(world *ecs.World, entity, target ecs.Entity) {
-	pos1Comp, _ := world.ComponentManager.GetComponent(entity, components.Position)
-	pos2Comp, _ := world.ComponentManager.GetComponent(target, components.Position)
-
-	pos1 := pos1Comp.(*components.PositionComponent)
-	pos2 := pos2Comp.(*components.PositionComponent)
-
-	dx, dy := 0, 0
-	if pos2.X > pos1.X {
-		dx = 1
-	} else if pos2.X < pos1.X {
-		dx = -1
+// mapToward returns the Dijkstra map toward the target, building it again only when the
+// target or the level is different from last time
+// Doors opened since it was built only make the way through them a step cheaper than it says
+func (ai *AISystem) mapToward(
+	tileMap *resources.TileMap,
+	target mathutils.Point,
+) *pathfinding.DijkstraMap {
+	if ai.towardPlayer != nil && ai.mapped == tileMap && ai.mappedPlayer == target {
+		return ai.towardPlayer
 	}
 
-	if pos2.Y > pos1.Y {
-		dy = 1
-	} else if pos2.Y < pos1.Y {
-		dy = -1
+	grid := pathfinding.Grid{
+		Width:  tileMap.Width,
+		Height: tileMap.Height,
+		Cost: func(x, y int) int {
+			if tileMap.At(x, y) == resources.ClosedDoor {
+				return closedDoorCost
+			}
+			return 1
+		},
+		Blocked: func(x, y int) bool {
+			return !tileMap.Passable(x, y)
+		},
 	}
-
-	world.ComponentManager.AddComponent(
-		entity,
-		components.MoveIntent,
-		&components.MoveIntentComponent{DX: dx, DY: dy},
-	)
+	ai.towardPlayer, ai.mapped, ai.mappedPlayer = grid.DijkstraMap(target), tileMap, target
+	return ai.towardPlayer
 }
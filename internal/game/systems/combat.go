@@ -12,67 +12,65 @@ type CombatSystem struct{}
 
 func (cs *CombatSystem) Update(world *ecs.World) {
 	// Get all entities with attack intent
-	entitiesWithAttackIntent := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.AttackIntent,
-	)
-
-	for _, entity := range entitiesWithAttackIntent {
-		// Get the attack intent component
-		attackIntentComp, hasIntent := world.ComponentManager.GetComponent(
-			entity,
-			components.AttackIntent,
-		)
-		if !hasIntent {
+	attackers := ecs.Query1[components.AttackIntentComponent](world)
+
+	for _, attacker := range attackers {
+		entity := attacker.Entity
+
+		// The intent is consumed whether or not the attack lands
+		world.Commands().Remove(entity, components.AttackIntent)
+
+		// Skip attackers that were defeated earlier in this update,
+		// they aren't despawned until the cleanup stage
+		if cs.isDefeated(entity, world) {
 			continue
 		}
 
-		attackIntent := attackIntentComp.(*components.AttackIntentComponent)
-		target := attackIntent.Target
+		target := attacker.A.Target
 
 		// Armor reduces damage
 		armor := cs.getEquipmentArmor(target, world)
 		damage := max(cs.getDamage(entity, world)-armor, 0)
 
-		// Check if the target exists and has health
-		healthComp, hasHealth := world.ComponentManager.GetComponent(target, components.Health)
-		if !hasHealth {
+		// Check if the target exists, has health and hasn't already been defeated
+		health, hasHealth := ecs.Get[components.HealthComponent](world, target)
+		if !hasHealth || health.HP <= 0 {
 			continue
 		}
 
-		health := healthComp.(*components.HealthComponent)
-
 		// Apply damage
 		health.HP -= damage
+		ecs.MarkChanged[components.HealthComponent](world, target)
 
 		// Queue an attack event
-		world.QueueEvent(events.EntityAttacked, entity, map[string]any{
-			"target": target,
-			"damage": damage,
+		ecs.Emit(world, events.EntityAttackedEventData{
+			Attacker: entity,
+			Target:   target,
+			Damage:   damage,
 		})
 
-		// Check if target is defeated
+		// Check if target is defeated, the defeat system removes it during cleanup
 		if health.HP <= 0 {
-			world.QueueEvent(events.EntityDefeated, target, nil)
-			world.RemoveEntity(target)
+			ecs.Emit(world, events.EntityDefeatedEventData{Entity: target})
 		}
-
-		// Remove the intent after processing
-		world.ComponentManager.RemoveComponent(entity, components.AttackIntent)
 	}
 }
 
+func (cs CombatSystem) isDefeated(ent ecs.Entity, world *ecs.World) bool {
+	health, hasHealth := ecs.Get[components.HealthComponent](world, ent)
+	return hasHealth && health.HP <= 0
+}
+
 func (cs CombatSystem) getEquipmentArmor(ent ecs.Entity, world *ecs.World) int {
-	inventoryComp, hasInventory := world.ComponentManager.GetComponent(ent, components.Inventory)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](world, ent)
 	if !hasInventory {
 		return 0
 	}
-	inventory := inventoryComp.(*components.InventoryComponent)
 
 	armor := 0
 	for _, itemEnt := range inventory.Slots {
-		armorComp, hasArmor := world.ComponentManager.GetComponent(itemEnt, components.Armor)
-		if hasArmor {
-			armor += armorComp.(*components.ArmorComponent).Defense
+		if armorComp, hasArmor := ecs.Get[components.ArmorComponent](world, itemEnt); hasArmor {
+			armor += armorComp.Defense
 		}
 	}
 
@@ -80,17 +78,15 @@ func (cs CombatSystem) getEquipmentArmor(ent ecs.Entity, world *ecs.World) int {
 }
 
 func (cs CombatSystem) getEquipmentDamage(ent ecs.Entity, world *ecs.World) int {
-	inventoryComp, hasInventory := world.ComponentManager.GetComponent(ent, components.Inventory)
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](world, ent)
 	if !hasInventory {
 		return 0
 	}
-	inventory := inventoryComp.(*components.InventoryComponent)
 
 	damage := 0
 	for _, itemEnt := range inventory.Slots {
-		weaponComp, hasWeapon := world.ComponentManager.GetComponent(itemEnt, components.Weapon)
-		if hasWeapon {
-			damage += weaponComp.(*components.WeaponComponent).Damage
+		if weapon, hasWeapon := ecs.Get[components.WeaponComponent](world, itemEnt); hasWeapon {
+			damage += weapon.Damage
 		}
 	}
 
@@ -98,11 +94,11 @@ func (cs CombatSystem) getEquipmentDamage(ent ecs.Entity, world *ecs.World) int
 }
 
 func (cs CombatSystem) getStrength(ent ecs.Entity, world *ecs.World) int {
-	strengthComp, hasStrength := world.ComponentManager.GetComponent(ent, components.Strength)
+	strength, hasStrength := ecs.Get[components.StrengthComponent](world, ent)
 	if !hasStrength {
 		return 0
 	}
-	return strengthComp.(*components.StrengthComponent).Strength
+	return strength.Strength
 }
 
 func (cs CombatSystem) getDamage(ent ecs.Entity, world *ecs.World) int {
@@ -0,0 +1,49 @@
+package systems
+
+import (
+	"ecs/internal/game/components"
+	"ecs/internal/game/resources"
+	"ecs/pkg/ecs"
+)
+
+// The Defeat System is responsible for removing defeated creatures
+// It rolls the drop table of every creature whose health ran out, spawns the drops
+// where the creature fell, then despawns the creature (and the items it carried)
+type DefeatSystem struct {
+	Prefabs *ecs.PrefabRegistry
+}
+
+func (ds *DefeatSystem) Update(world *ecs.World) {
+	creatures := ecs.Query1[components.HealthComponent](world)
+
+	rng, _ := ecs.Resource[resources.RNG](world)
+	for _, creature := range creatures {
+		entity, health := creature.Entity, creature.A
+		if health.HP > 0 {
+			continue
+		}
+
+		pos, hasPos := ecs.Get[components.PositionComponent](world, entity)
+		dropTable, hasDropTable := ecs.Get[components.DropTableComponent](world, entity)
+		if hasPos && hasDropTable && rng != nil {
+			for _, drop := range dropTable.Drops {
+				if rng.Float64() >= drop.Chance {
+					continue
+				}
+				ds.spawnDrop(world, drop.Prefab, pos.X, pos.Y)
+			}
+		}
+
+		world.Commands().Despawn(entity)
+	}
+}
+
+func (ds *DefeatSystem) spawnDrop(world *ecs.World, prefab string, x, y int) {
+	// Drops are checked against the prefabs when content is loaded
+	dropComponents, err := ds.Prefabs.Components(prefab)
+	if err != nil {
+		return
+	}
+	dropComponents = append(dropComponents, &components.PositionComponent{X: x, Y: y})
+	world.Commands().Spawn(dropComponents...)
+}
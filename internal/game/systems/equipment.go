@@ -13,35 +13,28 @@ import (
 type EquipmentSystem struct{}
 
 func (es *EquipmentSystem) Update(world *ecs.World) {
-	entitiesWithEquipIntent := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.EquipIntent,
-	)
+	entitiesWithEquipIntent := ecs.EntitiesWith[components.EquipIntentComponent](world)
 	for _, entity := range entitiesWithEquipIntent {
 		es.handleEquipIntent(entity, world)
 	}
 
-	entitiesWithUnequipIntent := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.UnequipIntent,
-	)
+	entitiesWithUnequipIntent := ecs.EntitiesWith[components.UnequipIntentComponent](world)
 	for _, entity := range entitiesWithUnequipIntent {
 		es.handleUnequipIntent(entity, world)
 	}
 }
 
 func (es *EquipmentSystem) handleEquipIntent(ent ecs.Entity, world *ecs.World) {
-	equipIntentComp, _ := world.ComponentManager.GetComponent(ent, components.EquipIntent)
-	equipIntent := equipIntentComp.(*components.EquipIntentComponent)
+	equipIntent, _ := ecs.Get[components.EquipIntentComponent](world, ent)
 
-	equippableComp, hasEquippableComp := world.ComponentManager.GetComponent(
+	equippable, hasEquippableComp := ecs.Get[components.EquippableComponent](
+		world,
 		equipIntent.ItemEntity,
-		components.Equippable,
 	)
 	if !hasEquippableComp {
 		return
 	}
 
-	equippable := equippableComp.(*components.EquippableComponent)
-
 	// Check if the item can be equipped in the specified slot
 	if !es.canEquipInSlot(equipIntent.Slot, equippable) {
 		return
@@ -52,11 +45,7 @@ func (es *EquipmentSystem) handleEquipIntent(ent ecs.Entity, world *ecs.World) {
 		return
 	}
 
-	inventoryComp, _ := world.ComponentManager.GetComponent(
-		equipIntent.Target,
-		components.Inventory,
-	)
-	inventory := inventoryComp.(*components.InventoryComponent)
+	inventory, _ := ecs.GetMut[components.InventoryComponent](world, equipIntent.Target)
 
 	// Add the item to the equipment slot
 	inventory.Slots[equipIntent.Slot] = equipIntent.ItemEntity
@@ -69,18 +58,18 @@ func (es *EquipmentSystem) handleEquipIntent(ent ecs.Entity, world *ecs.World) {
 		}
 	}
 	// Queue event
-	world.QueueEvent(events.ItemEquipped, ent, map[string]any{
-		"item":   equipIntent.ItemEntity,
-		"target": equipIntent.Target,
+	ecs.Emit(world, events.ItemEquippedEventData{
+		Entity: ent,
+		Item:   equipIntent.ItemEntity,
+		Target: equipIntent.Target,
 	})
 
 	// Remove the equip intent component
-	world.ComponentManager.RemoveComponent(ent, components.EquipIntent)
+	world.Commands().Remove(ent, components.EquipIntent)
 }
 
 func (es *EquipmentSystem) handleUnequipIntent(ent ecs.Entity, world *ecs.World) {
-	unequipIntentComp, _ := world.ComponentManager.GetComponent(ent, components.UnequipIntent)
-	unequipIntent := unequipIntentComp.(*components.UnequipIntentComponent)
+	unequipIntent, _ := ecs.Get[components.UnequipIntentComponent](world, ent)
 
 	// Check if the slot is occupied
 	if !es.isSlotOccupied(unequipIntent.Target, unequipIntent.Slot, world) {
@@ -88,11 +77,7 @@ func (es *EquipmentSystem) handleUnequipIntent(ent ecs.Entity, world *ecs.World)
 	}
 
 	// Get the item entity from the equipment slot
-	inventoryComp, _ := world.ComponentManager.GetComponent(
-		unequipIntent.Target,
-		components.Inventory,
-	)
-	inventory := inventoryComp.(*components.InventoryComponent)
+	inventory, _ := ecs.GetMut[components.InventoryComponent](world, unequipIntent.Target)
 
 	itemEntity := inventory.Slots[unequipIntent.Slot]
 
@@ -103,13 +88,14 @@ func (es *EquipmentSystem) handleUnequipIntent(ent ecs.Entity, world *ecs.World)
 	inventory.Items = append(inventory.Items, itemEntity)
 
 	// Queue event
-	world.QueueEvent(events.ItemUnequipped, ent, map[string]any{
-		"item":   itemEntity,
-		"target": unequipIntent.Target,
+	ecs.Emit(world, events.ItemUnequippedEventData{
+		Entity: ent,
+		Item:   itemEntity,
+		Target: unequipIntent.Target,
 	})
 
 	// Remove the unequip intent component
-	world.ComponentManager.RemoveComponent(ent, components.UnequipIntent)
+	world.Commands().Remove(ent, components.UnequipIntent)
 }
 
 func (es *EquipmentSystem) canEquipInSlot(
@@ -124,16 +110,11 @@ func (es *EquipmentSystem) isSlotOccupied(
 	slot components.EquipmentSlot,
 	world *ecs.World,
 ) bool {
-	inventoryComp, hasInventoryComp := world.ComponentManager.GetComponent(
-		target,
-		components.Inventory,
-	)
+	inventory, hasInventoryComp := ecs.Get[components.InventoryComponent](world, target)
 	if !hasInventoryComp {
 		return false
 	}
 
-	inventory := inventoryComp.(*components.InventoryComponent)
-
 	// If the slot is occupied, return true
 	if _, ok := inventory.Slots[slot]; ok {
 		return true
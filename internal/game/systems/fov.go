@@ -0,0 +1,43 @@
+package systems
+
+import (
+	"ecs/internal/game/components"
+	"ecs/internal/game/fov"
+	"ecs/internal/game/resources"
+	"ecs/pkg/ecs"
+)
+
+// The FOV System is responsible for working out what each entity can see
+// Walls and doors block sight. Every tile the player sees is remembered as explored
+// on the tile map, so it can still be drawn once it is out of view
+type FOVSystem struct{}
+
+func (fs *FOVSystem) Update(world *ecs.World) {
+	tileMap, hasTileMap := ecs.Resource[resources.TileMap](world)
+	if !hasTileMap {
+		return
+	}
+
+	viewers := ecs.Query2[components.FieldOfViewComponent, components.PositionComponent](world)
+	for _, viewer := range viewers {
+		fieldOfView, pos := viewer.A, viewer.B
+		isPlayer := ecs.Has[components.PlayerControlledComponent](world, viewer.Entity)
+
+		// Start from nothing in view, reusing the last turn's tiles when the map is the same size
+		if len(fieldOfView.Visible) != len(tileMap.Tiles) {
+			fieldOfView.Visible = make([]bool, len(tileMap.Tiles))
+		}
+		clear(fieldOfView.Visible)
+
+		fov.Compute(pos.X, pos.Y, fieldOfView.Radius, tileMap.BlocksSight, func(x, y int) {
+			if !tileMap.InBounds(x, y) {
+				return
+			}
+			fieldOfView.Visible[y*tileMap.Width+x] = true
+			if isPlayer {
+				tileMap.Explore(x, y)
+			}
+		})
+		ecs.MarkChanged[components.FieldOfViewComponent](world, viewer.Entity)
+	}
+}
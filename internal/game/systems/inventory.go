@@ -3,69 +3,56 @@ package systems
 import (
 	"ecs/internal/game/components"
 	"ecs/internal/game/events"
+	"ecs/internal/game/spatial"
 	"ecs/pkg/ecs"
 )
 
 // The Inventory System is responsible for handling pickup intents
 // It consumes pickup intents and adds items to the entity's inventory (if valid)
-type InventorySystem struct{}
+type InventorySystem struct {
+	Index *spatial.Index
+}
 
 func (is *InventorySystem) Update(world *ecs.World) {
-	// Process all entities with PickupIntentComponent
-	entitiesWithPickupIntent := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.PickupIntent,
+	// Process all entities with PickupIntentComponent that have a position and an inventory
+	pickers := ecs.Query2[components.PositionComponent, components.InventoryComponent](
+		world,
+		ecs.With[components.PickupIntentComponent](),
 	)
 
-	for _, entity := range entitiesWithPickupIntent {
-		// Get entity position
-		entityPosComp, hasPosComp := world.ComponentManager.GetComponent(
-			entity,
-			components.Position,
-		)
-		if !hasPosComp {
-			continue
-		}
-		entityPos := entityPosComp.(*components.PositionComponent)
+	pickedUp := map[ecs.Entity]bool{}
+	for _, picker := range pickers {
+		entity, entityPos, inventory := picker.Entity, picker.A, picker.B
 
-		// Check if entity has an inventory
-		inventoryComp, hasInventory := world.ComponentManager.GetComponent(
-			entity,
-			components.Inventory,
+		// Items still lying in the world have a position, items in an inventory don't
+		items := is.Index.EntitiesAt(
+			entityPos.X, entityPos.Y,
+			ecs.With[components.ItemComponent](),
 		)
-		if !hasInventory {
-			continue
-		}
-		inventory := inventoryComp.(*components.InventoryComponent)
-
-		itemEntities := world.ComponentManager.GetAllEntitiesWithComponent(components.Item)
-		for _, itemEntity := range itemEntities {
-			// Skip if the item is already in the inventory
-			itemPosComp, hasItemPos := world.ComponentManager.GetComponent(
-				itemEntity,
-				components.Position,
-			)
-			if !hasItemPos {
+		for _, itemEntity := range items {
+			// Skip items another entity picked up during this update
+			if pickedUp[itemEntity] {
 				continue
 			}
 
-			itemPos := itemPosComp.(*components.PositionComponent)
+			pickedUp[itemEntity] = true
 
-			// Check if item has the same position as the entity
-			if itemPos.X == entityPos.X && itemPos.Y == entityPos.Y {
-				// Add item to inventory
-				inventory.Items = append(inventory.Items, itemEntity)
+			// Add item to inventory
+			inventory.Items = append(inventory.Items, itemEntity)
+			ecs.MarkChanged[components.InventoryComponent](world, entity)
 
-				// Remove item from world position
-				world.ComponentManager.RemoveComponent(itemEntity, components.Position)
+			// Remove item from world position, it now belongs to the entity
+			world.Commands().Remove(itemEntity, components.Position)
+			world.Commands().Add(itemEntity, &ecs.ChildOf{Parent: entity})
 
-				// Queue inventory_changed event
-				world.QueueEvent(events.ItemPickedUp, entity, map[string]any{
-					"item": itemEntity,
-				})
-			}
+			// Queue inventory_changed event
+			ecs.Emit(world, events.ItemPickedUpEventData{
+				Entity: entity,
+				Item:   itemEntity,
+			})
 		}
 
 		// Remove item from world position
-		world.ComponentManager.RemoveComponent(entity, components.PickupIntent)
+		world.Commands().Remove(entity, components.PickupIntent)
 	}
 }
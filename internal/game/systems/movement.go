@@ -3,47 +3,128 @@ package systems
 import (
 	"ecs/internal/game/components"
 	"ecs/internal/game/events"
+	"ecs/internal/game/resources"
+	"ecs/internal/game/spatial"
 	"ecs/pkg/ecs"
+	"ecs/pkg/mathutils"
 )
 
 // The Movement System is responsible for handling movement intents
-// It consumes move intents and updates the entity's position
-type MovementSystem struct{}
+// It consumes move intents and updates the entity's position, unless the map or an entity
+// that blocks is in the way. Bumping into a closed door opens it instead, and with
+// BumpToAttack set, bumping into an enemy attacks it
+type MovementSystem struct {
+	Index        *spatial.Index // Where blockers are looked up, nothing blocks when nil
+	BumpToAttack bool
+}
 
 func (ms *MovementSystem) Update(world *ecs.World) {
-	// Get all entities with movement intent
-	entitiesWithMoveIntent := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.MoveIntent,
-	)
-
-	for _, entity := range entitiesWithMoveIntent {
-		// Get the components we need
-		moveIntentComp, hasIntent := world.ComponentManager.GetComponent(
-			entity,
-			components.MoveIntent,
-		)
-		posComp, hasPos := world.ComponentManager.GetComponent(entity, components.Position)
-
-		if !hasIntent || !hasPos {
+	// Get all entities with movement intent and a position to move
+	movers := ecs.Query2[components.MoveIntentComponent, components.PositionComponent](world)
+
+	tileMap, hasTileMap := ecs.Resource[resources.TileMap](world)
+
+	// Tiles moved onto during this update, which the index doesn't know about until it runs
+	claimed := map[mathutils.Point]ecs.Entity{}
+
+	for _, mover := range movers {
+		entity, moveIntent, pos := mover.Entity, mover.A, mover.B
+
+		// The intent is consumed whether or not the move is allowed
+		world.Commands().Remove(entity, components.MoveIntent)
+
+		// Boundary and terrain check
+		targetX, targetY := pos.X+moveIntent.DX, pos.Y+moveIntent.DY
+		if hasTileMap && !tileMap.InBounds(targetX, targetY) {
+			ms.blocked(world, entity, targetX, targetY, events.BlockedByBounds, -1)
+			continue
+		}
+		if hasTileMap && tileMap.At(targetX, targetY) == resources.ClosedDoor {
+			tileMap.Set(targetX, targetY, resources.Door)
+			ecs.Emit(world, events.DoorOpenedEventData{Entity: entity, X: targetX, Y: targetY})
+			continue
+		}
+		if hasTileMap && !tileMap.Walkable(targetX, targetY) {
+			ms.blocked(world, entity, targetX, targetY, events.BlockedByTerrain, -1)
 			continue
 		}
 
-		moveIntent := moveIntentComp.(*components.MoveIntentComponent)
-		pos := posComp.(*components.PositionComponent)
+		// Occupancy check
+		if blocker, isBlocked := ms.blockerAt(world, entity, targetX, targetY, claimed); isBlocked {
+			if ms.BumpToAttack && hostile(world, entity, blocker) {
+				world.Commands().Add(entity, &components.AttackIntentComponent{Target: blocker})
+			} else {
+				ms.blocked(world, entity, targetX, targetY, events.BlockedByEntity, blocker)
+			}
+			continue
+		}
 
 		// Update position
 		pos.X += moveIntent.DX
 		pos.Y += moveIntent.DY
-
-		// Remove the intent after processing
-		world.ComponentManager.RemoveComponent(entity, components.MoveIntent)
+		ecs.MarkChanged[components.PositionComponent](world, entity)
+		if ecs.Has[components.BlockerComponent](world, entity) {
+			claimed[mathutils.Point{X: pos.X, Y: pos.Y}] = entity
+		}
 
 		// QUeue a movement event for other systems (like renderer)
-		world.QueueEvent(events.EntityMoved, entity, map[string]any{
-			"old_x": pos.X - moveIntent.DX,
-			"old_y": pos.Y - moveIntent.DY,
-			"new_x": pos.X,
-			"new_y": pos.Y,
+		ecs.Emit(world, events.EntityMovedEventData{
+			Entity: entity,
+			OldX:   pos.X - moveIntent.DX,
+			OldY:   pos.Y - moveIntent.DY,
+			NewX:   pos.X,
+			NewY:   pos.Y,
 		})
 	}
 }
+
+// blockerAt returns the entity that blocks the tile, if any, other than the mover
+// Entities that already moved this update are checked where they are now, not where the
+// index last saw them
+func (ms *MovementSystem) blockerAt(
+	world *ecs.World,
+	mover ecs.Entity,
+	x, y int,
+	claimed map[mathutils.Point]ecs.Entity,
+) (ecs.Entity, bool) {
+	if blocker, isClaimed := claimed[mathutils.Point{X: x, Y: y}]; isClaimed {
+		return blocker, true
+	}
+	if ms.Index == nil {
+		return -1, false
+	}
+
+	for _, entity := range ms.Index.EntitiesAt(x, y, ecs.With[components.BlockerComponent]()) {
+		if entity == mover {
+			continue
+		}
+		if pos, hasPos := ecs.Get[components.PositionComponent](world, entity); hasPos &&
+			pos.X == x && pos.Y == y {
+			return entity, true
+		}
+	}
+	return -1, false
+}
+
+// blocked lets everyone know why a move didn't happen
+func (ms *MovementSystem) blocked(
+	world *ecs.World,
+	entity ecs.Entity,
+	x, y int,
+	reason events.MoveBlockedReason,
+	blocker ecs.Entity,
+) {
+	ecs.Emit(world, events.MoveBlockedEventData{
+		Entity:  entity,
+		X:       x,
+		Y:       y,
+		Reason:  reason,
+		Blocker: blocker,
+	})
+}
+
+// hostile reports whether the entities are on opposite sides, ie. a monster and the player
+func hostile(world *ecs.World, a, b ecs.Entity) bool {
+	return ecs.Has[components.HostileComponent](world, a) !=
+		ecs.Has[components.HostileComponent](world, b)
+}
@@ -15,39 +15,26 @@ type UsableSystem struct{}
 
 func (us *UsableSystem) Update(world *ecs.World) {
 	// Process all entities with use item intent
-	entitiesWithUseItemIntent := world.ComponentManager.GetAllEntitiesWithComponent(
-		components.UseItemIntent,
-	)
+	entitiesWithUseItemIntent := ecs.EntitiesWith[components.UseItemIntentComponent](world)
 
 	for _, entity := range entitiesWithUseItemIntent {
-		useIntentComp, _ := world.ComponentManager.GetComponent(entity, components.UseItemIntent)
-		useIntent := useIntentComp.(*components.UseItemIntentComponent)
+		useIntent, _ := ecs.Get[components.UseItemIntentComponent](world, entity)
 
-		usableComp, hasUsableComp := world.ComponentManager.GetComponent(
-			useIntent.ItemEntity,
-			components.Usable,
-		)
+		usable, hasUsableComp := ecs.Get[components.UsableComponent](world, useIntent.ItemEntity)
 		if !hasUsableComp {
 			return
 		}
 
-		usable := usableComp.(*components.UsableComponent)
-
 		switch usable.Effect {
 		case components.HealEffect:
-			if healthComp, hasHealthComp := world.ComponentManager.GetComponent(useIntent.Target, components.Health); hasHealthComp {
-				health := healthComp.(*components.HealthComponent)
+			if health, hasHealthComp := ecs.Get[components.HealthComponent](world, useIntent.Target); hasHealthComp {
 
 				if health.HP == health.MaxHP {
 					continue
 				}
 
 				// Remove the item from the inventory
-				inventoryComp, _ := world.ComponentManager.GetComponent(
-					useIntent.Consumer,
-					components.Inventory,
-				)
-				inventory := inventoryComp.(*components.InventoryComponent)
+				inventory, _ := ecs.GetMut[components.InventoryComponent](world, useIntent.Consumer)
 
 				for i, item := range inventory.Items {
 					if item == useIntent.ItemEntity {
@@ -60,27 +47,25 @@ func (us *UsableSystem) Update(world *ecs.World) {
 				if health.HP > health.MaxHP {
 					health.HP = health.MaxHP
 				}
+				ecs.MarkChanged[components.HealthComponent](world, useIntent.Target)
 
-				// Remove the usable component from the item
-				world.ComponentManager.RemoveComponent(useIntent.ItemEntity, components.Usable)
+				// The item is used up
+				world.Commands().Despawn(useIntent.ItemEntity)
 
 				// Queue event
-				world.QueueEvent(events.ItemUsed, entity, map[string]any{
-					"item":   useIntent.ItemEntity,
-					"target": useIntent.Target,
+				ecs.Emit(world, events.ItemUsedEventData{
+					Entity:   entity,
+					Item:     useIntent.ItemEntity,
+					ItemName: itemName(world, useIntent.ItemEntity),
+					Target:   useIntent.Target,
 				})
 
 			}
 		case components.DamageEffect:
-			if healthComp, hasHealthComp := world.ComponentManager.GetComponent(useIntent.Target, components.Health); hasHealthComp {
-				health := healthComp.(*components.HealthComponent)
+			if health, hasHealthComp := ecs.Get[components.HealthComponent](world, useIntent.Target); hasHealthComp {
 
 				// Remove the item from the inventory
-				inventoryComp, _ := world.ComponentManager.GetComponent(
-					useIntent.Consumer,
-					components.Inventory,
-				)
-				inventory := inventoryComp.(*components.InventoryComponent)
+				inventory, _ := ecs.GetMut[components.InventoryComponent](world, useIntent.Consumer)
 
 				for i, item := range inventory.Items {
 					if item == useIntent.ItemEntity {
@@ -90,24 +75,35 @@ func (us *UsableSystem) Update(world *ecs.World) {
 				}
 
 				health.HP -= usable.Power
+				ecs.MarkChanged[components.HealthComponent](world, useIntent.Target)
 				if health.HP <= 0 {
 					health.HP = 0
-					world.QueueEvent(events.EntityDefeated, useIntent.Target, nil)
+					ecs.Emit(world, events.EntityDefeatedEventData{Entity: useIntent.Target})
 				}
 
-				// Remove the usable component from the item
-				world.ComponentManager.RemoveComponent(useIntent.ItemEntity, components.Usable)
+				// The item is used up
+				world.Commands().Despawn(useIntent.ItemEntity)
 
 				// Queue event
-				world.QueueEvent(events.ItemUsed, entity, map[string]any{
-					"item":   useIntent.ItemEntity,
-					"target": useIntent.Target,
+				ecs.Emit(world, events.ItemUsedEventData{
+					Entity:   entity,
+					Item:     useIntent.ItemEntity,
+					ItemName: itemName(world, useIntent.ItemEntity),
+					Target:   useIntent.Target,
 				})
 			}
 		case components.RepairEffect:
 		}
 
 		// Remove the use item intent component
-		world.ComponentManager.RemoveComponent(entity, components.UseItemIntent)
+		world.Commands().Remove(entity, components.UseItemIntent)
+	}
+}
+
+// itemName returns the name of the item, for events sent after it has been used up
+func itemName(world *ecs.World, itemEntity ecs.Entity) string {
+	if item, hasItem := ecs.Get[components.ItemComponent](world, itemEntity); hasItem {
+		return item.Name
 	}
+	return ""
 }
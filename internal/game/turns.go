@@ -0,0 +1,64 @@
+package game
+
+import (
+	"ecs/internal/game/components"
+	"ecs/internal/game/events"
+	"ecs/internal/turnmanager"
+	"ecs/pkg/ecs"
+)
+
+// How much energy actions cost, where turnmanager.ActionCost is an ordinary action
+// Turns where nothing happened (ie. a blocked move) cost an ordinary action too
+const (
+	moveCost   = turnmanager.ActionCost
+	attackCost = turnmanager.ActionCost
+	itemCost   = turnmanager.ActionCost
+
+	// Attacks cost this much more for every point of weight of the wielded weapons, so
+	// heavy weapons swing slower
+	weaponWeightCost = 5
+)
+
+// subscribeActionCosts charges entities for their actions, by the events the actions emit
+func (g *Game) subscribeActionCosts() {
+	ecs.Subscribe(g.world, func(event events.EntityMovedEventData) {
+		g.turnManager.Charge(event.Entity, moveCost)
+	})
+	ecs.Subscribe(g.world, func(event events.DoorOpenedEventData) {
+		g.turnManager.Charge(event.Entity, moveCost)
+	})
+	ecs.Subscribe(g.world, func(event events.EntityAttackedEventData) {
+		g.turnManager.Charge(event.Attacker, g.attackCost(event.Attacker))
+	})
+	ecs.Subscribe(g.world, func(event events.ItemPickedUpEventData) {
+		g.turnManager.Charge(event.Entity, itemCost)
+	})
+	ecs.Subscribe(g.world, func(event events.ItemUsedEventData) {
+		g.turnManager.Charge(event.Entity, itemCost)
+	})
+	ecs.Subscribe(g.world, func(event events.ItemEquippedEventData) {
+		g.turnManager.Charge(event.Entity, itemCost)
+	})
+	ecs.Subscribe(g.world, func(event events.ItemUnequippedEventData) {
+		g.turnManager.Charge(event.Entity, itemCost)
+	})
+}
+
+// attackCost returns what an attack by the entity costs, with the weapons it has equipped
+func (g *Game) attackCost(entity ecs.Entity) int {
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](g.world, entity)
+	if !hasInventory {
+		return attackCost
+	}
+
+	cost := attackCost
+	for _, itemEnt := range inventory.Slots {
+		if !ecs.Has[components.WeaponComponent](g.world, itemEnt) {
+			continue
+		}
+		if item, hasItem := ecs.Get[components.ItemComponent](g.world, itemEnt); hasItem {
+			cost += item.Weight * weaponWeightCost
+		}
+	}
+	return cost
+}
@@ -1,33 +1,69 @@
 package turnmanager
 
 import (
+	"maps"
 	"slices"
 
 	"ecs/pkg/ecs"
 )
 
+// ActionCost is the energy an entity needs to take a turn, and what an ordinary action
+// (ie. a step) costs
+const ActionCost = 100
+
+// SpeedFunc returns how much energy the entity gains every tick, where ActionCost is
+// enough to act once every tick
+type SpeedFunc func(entity ecs.Entity) int
+
+// TurnManager decides whose turn it is by energy
+// Every tick, entities gain energy by their speed, and the entity with the most energy
+// takes a turn once it has at least ActionCost. Its action then spends what it cost, so
+// faster entities act more often, and cheaper actions come round sooner
+// Entities with the same energy act in turn order, starting after the entity that acted last
+// Entities that leave the turn order keep their energy, in case they come back to it
 type TurnManager struct {
 	world     *ecs.World
+	speedOf   SpeedFunc
 	turnOrder []ecs.Entity
 	current   int
+	energy    map[ecs.Entity]int // Of the entities in the turn order, and those that left it
+	acting    ecs.Entity         // Entity whose turn it is, even if it was removed during its turn
+	cost      int                // What the acting entity has been charged this turn
 }
 
-func NewTurnManager(world *ecs.World) *TurnManager {
+// NewTurnManager creates a turn manager where entities gain energy by speedOf
+// Every entity acts once a tick when speedOf is nil
+func NewTurnManager(world *ecs.World, speedOf SpeedFunc) *TurnManager {
 	return &TurnManager{
 		world:     world,
+		speedOf:   speedOf,
 		turnOrder: []ecs.Entity{},
 		current:   0,
+		energy:    map[ecs.Entity]int{},
+		acting:    -1,
 	}
 }
 
 func (tm *TurnManager) AddEntity(entity ecs.Entity) {
+	if slices.Contains(tm.turnOrder, entity) {
+		return
+	}
 	tm.turnOrder = append(tm.turnOrder, entity)
+	if _, known := tm.energy[entity]; !known {
+		tm.energy[entity] = 0
+	}
+
+	// The first entity added takes the first turn
+	if len(tm.turnOrder) == 1 {
+		tm.current, tm.acting = 0, entity
+	}
 }
 
 func (tm *TurnManager) RemoveEntity(entity ecs.Entity) {
 	for i, e := range tm.turnOrder {
 		if e == entity {
 			tm.turnOrder = slices.Delete(tm.turnOrder, i, i+1)
+			delete(tm.energy, entity)
 			if tm.current >= i && tm.current > 0 {
 				tm.current--
 			}
@@ -36,21 +72,86 @@ func (tm *TurnManager) RemoveEntity(entity ecs.Entity) {
 	}
 }
 
+// Charge sets what the entity's current turn costs
+// An action can be charged more than once (ie. picking up several items), in which case it
+// costs the most it was charged. Entities that aren't taking their turn aren't charged
+func (tm *TurnManager) Charge(entity ecs.Entity, cost int) {
+	if entity == tm.acting {
+		tm.cost = max(tm.cost, cost)
+	}
+}
+
+// NextTurn ends the current entity's turn, spending what it was charged (or ActionCost if
+// it wasn't charged, ie. it waited), and returns the entity that takes the next turn
 func (tm *TurnManager) NextTurn() ecs.Entity {
-	if len(tm.turnOrder) == 0 {
-		return -1
+	if slices.Contains(tm.turnOrder, tm.acting) {
+		if tm.cost == 0 {
+			tm.cost = ActionCost
+		}
+		tm.energy[tm.acting] -= tm.cost
 	}
+	tm.cost = 0
 
-	tm.current = (tm.current + 1) % len(tm.turnOrder)
-	currentEntity := tm.turnOrder[tm.current]
+	for len(tm.turnOrder) > 0 {
+		next, ready := tm.readiest()
+		if !ready {
+			tm.tick()
+			continue
+		}
 
-	// Make sure entity still exists
-	if !tm.world.EntityManager.HasEntity(currentEntity) {
-		tm.RemoveEntity(currentEntity)
-		return tm.NextTurn() // Skip to next entity
+		// Make sure entity still exists
+		entity := tm.turnOrder[next]
+		if !tm.world.IsAlive(entity) {
+			tm.RemoveEntity(entity)
+			continue // Skip to next entity
+		}
+
+		tm.current, tm.acting = next, entity
+		return entity
 	}
 
-	return currentEntity
+	tm.acting = -1
+	return -1
+}
+
+// readiest returns the index of the entity with the most energy, if it has enough to act
+// Ties go to whoever comes first in turn order after the current entity, so entities of
+// the same speed take turns round-robin
+func (tm *TurnManager) readiest() (int, bool) {
+	best, bestEnergy := -1, ActionCost-1
+	for offset := 1; offset <= len(tm.turnOrder); offset++ {
+		i := (tm.current + offset) % len(tm.turnOrder)
+		if energy := tm.energy[tm.turnOrder[i]]; energy > bestEnergy {
+			best, bestEnergy = i, energy
+		}
+	}
+	return best, best != -1
+}
+
+// tick gives every entity energy by its speed, as many times as it takes for the first
+// of them to be able to act
+func (tm *TurnManager) tick() {
+	ticks := -1
+	for _, entity := range tm.turnOrder {
+		needed := ActionCost - tm.energy[entity]
+		speed := tm.speed(entity)
+		if entityTicks := (needed + speed - 1) / speed; ticks == -1 || entityTicks < ticks {
+			ticks = entityTicks
+		}
+	}
+	ticks = max(ticks, 1)
+
+	for _, entity := range tm.turnOrder {
+		tm.energy[entity] += tm.speed(entity) * ticks
+	}
+}
+
+// speed returns how much energy the entity gains every tick
+func (tm *TurnManager) speed(entity ecs.Entity) int {
+	if tm.speedOf == nil {
+		return ActionCost
+	}
+	return max(tm.speedOf(entity), 1)
 }
 
 func (tm *TurnManager) GetCurrentEntity() ecs.Entity {
@@ -61,9 +162,47 @@ func (tm *TurnManager) GetCurrentEntity() ecs.Entity {
 	return tm.turnOrder[tm.current]
 }
 
+// TurnOrder returns the entities in the order they take turns, and the index of the
+// entity whose turn it is
+func (tm *TurnManager) TurnOrder() ([]ecs.Entity, int) {
+	return slices.Clone(tm.turnOrder), tm.current
+}
+
+// SetTurnOrder replaces the turn order, ie. with one saved from TurnOrder, starting with
+// the turn of the entity at current
+// Entities keep the energy they had, even if they were taken out of the turn order before
+// (ie. on a level the player left), and start with none otherwise
+func (tm *TurnManager) SetTurnOrder(turnOrder []ecs.Entity, current int) {
+	for _, entity := range turnOrder {
+		if _, known := tm.energy[entity]; !known {
+			tm.energy[entity] = 0
+		}
+	}
+
+	tm.turnOrder = slices.Clone(turnOrder)
+	tm.current = 0
+	if current >= 0 && current < len(tm.turnOrder) {
+		tm.current = current
+	}
+	tm.acting, tm.cost = tm.GetCurrentEntity(), 0
+}
+
+// Energy returns the energy of every entity in the turn order, and of those that left it
+func (tm *TurnManager) Energy() map[ecs.Entity]int {
+	return maps.Clone(tm.energy)
+}
+
+// SetEnergy replaces the energy of every entity, ie. with what was saved from Energy
+// Entities that aren't in it have none
+func (tm *TurnManager) SetEnergy(energy map[ecs.Entity]int) {
+	tm.energy = map[ecs.Entity]int{}
+	maps.Copy(tm.energy, energy)
+}
+
 func (tm *TurnManager) RegisterEntities() {
 	// Clear turn order to rebuild it
 	tm.turnOrder = []ecs.Entity{}
+	tm.energy = map[ecs.Entity]int{}
 	tm.current = 0
 
 	// Add all entities with relevant components
@@ -72,4 +211,5 @@ func (tm *TurnManager) RegisterEntities() {
 	for _, entity := range entities {
 		tm.AddEntity(entity)
 	}
+	tm.acting, tm.cost = tm.GetCurrentEntity(), 0
 }
@@ -0,0 +1,191 @@
+package turnmanager
+
+import (
+	"io"
+	"log"
+	"slices"
+	"testing"
+
+	"ecs/pkg/ecs"
+)
+
+// normal is the speed of most entities in the tests, which act once every tick
+const normal = ActionCost
+
+// newTurnManager returns a turn manager with an entity of each speed, in turn order
+func newTurnManager(speeds ...int) (*TurnManager, []ecs.Entity) {
+	world := ecs.NewWorld(log.New(io.Discard, "", 0))
+	speedOf := map[ecs.Entity]int{}
+	tm := NewTurnManager(world, func(entity ecs.Entity) int {
+		return speedOf[entity]
+	})
+	entities := []ecs.Entity{}
+	for _, speed := range speeds {
+		entity := world.EntityManager.CreateEntity()
+		speedOf[entity] = speed
+		tm.AddEntity(entity)
+		entities = append(entities, entity)
+	}
+	return tm, entities
+}
+
+// takeTurns ends the given number of turns, charging each entity what cost returns for it,
+// and returns whose turns they were
+func takeTurns(tm *TurnManager, turns int, cost func(ecs.Entity) int) []ecs.Entity {
+	taken := []ecs.Entity{}
+	for range turns {
+		if cost != nil {
+			acting := tm.GetCurrentEntity()
+			tm.Charge(acting, cost(acting))
+		}
+		taken = append(taken, tm.NextTurn())
+	}
+	return taken
+}
+
+// countTurns returns how many of the turns each entity took
+func countTurns(taken []ecs.Entity, entities []ecs.Entity) []int {
+	counts := make([]int, len(entities))
+	for _, entity := range taken {
+		counts[slices.Index(entities, entity)]++
+	}
+	return counts
+}
+
+func TestSpeedRatios(t *testing.T) {
+	tm, entities := newTurnManager(
+		2*normal,
+		normal,
+		normal/2,
+	)
+
+	// Every 7 turns, the fast entity takes 4, the normal one 2 and the slow one 1
+	counts := countTurns(takeTurns(tm, 700, nil), entities)
+	want := []int{400, 200, 100}
+	for i := range want {
+		if counts[i] < want[i]-2 || counts[i] > want[i]+2 {
+			t.Fatalf("turns = %v, want about %v", counts, want)
+		}
+	}
+}
+
+func TestEqualSpeedsTakeTurnsInOrder(t *testing.T) {
+	tm, entities := newTurnManager(normal, normal, normal)
+
+	// The first entity has the first turn, so the others follow it round-robin
+	taken := takeTurns(tm, 6, nil)
+	want := []ecs.Entity{entities[1], entities[2], entities[0], entities[1], entities[2], entities[0]}
+	if !slices.Equal(taken, want) {
+		t.Fatalf("turns = %v, want %v", taken, want)
+	}
+}
+
+func TestRemovingActingEntity(t *testing.T) {
+	tm, entities := newTurnManager(normal, normal, normal)
+	if acting := tm.NextTurn(); acting != entities[1] {
+		t.Fatalf("acting = %v, want %v", acting, entities[1])
+	}
+
+	// The entity dies during its turn, so what it did isn't charged to anyone else, and
+	// the turn passes to whoever was after it
+	tm.world.RemoveEntity(entities[1])
+	tm.RemoveEntity(entities[1])
+	tm.Charge(entities[1], 10*ActionCost)
+
+	taken := takeTurns(tm, 4, nil)
+	want := []ecs.Entity{entities[2], entities[0], entities[2], entities[0]}
+	if !slices.Equal(taken, want) {
+		t.Fatalf("turns = %v, want %v", taken, want)
+	}
+}
+
+func TestDespawnedEntitiesAreSkipped(t *testing.T) {
+	tm, entities := newTurnManager(normal, normal, normal)
+
+	// Entities removed from the world but not the turn order lose their turns
+	tm.world.RemoveEntity(entities[1])
+	taken := takeTurns(tm, 4, nil)
+	want := []ecs.Entity{entities[2], entities[0], entities[2], entities[0]}
+	if !slices.Equal(taken, want) {
+		t.Fatalf("turns = %v, want %v", taken, want)
+	}
+	if order, _ := tm.TurnOrder(); slices.Contains(order, entities[1]) {
+		t.Fatalf("turn order = %v, still has %v", order, entities[1])
+	}
+}
+
+func TestCharge(t *testing.T) {
+	t.Run("heavy actions come round slower", func(t *testing.T) {
+		tm, entities := newTurnManager(normal, normal)
+		heavy := entities[0]
+
+		// Swinging a heavy weapon costs half as much again as a step, so the entity that
+		// does it acts twice for every three turns of the other
+		taken := takeTurns(tm, 500, func(entity ecs.Entity) int {
+			if entity == heavy {
+				return ActionCost * 3 / 2
+			}
+			return ActionCost
+		})
+		counts := countTurns(taken, entities)
+		want := []int{200, 300}
+		for i := range want {
+			if counts[i] < want[i]-2 || counts[i] > want[i]+2 {
+				t.Fatalf("turns = %v, want about %v", counts, want)
+			}
+		}
+	})
+
+	t.Run("the most an action was charged is spent", func(t *testing.T) {
+		tm, entities := newTurnManager(normal)
+		tm.Charge(entities[0], ActionCost/2)
+		tm.Charge(entities[0], ActionCost*3/2)
+		tm.Charge(entities[0], ActionCost)
+
+		// Spending 150 leaves -150, and it takes 3 ticks of 100 to be able to act again
+		if acting := tm.NextTurn(); acting != entities[0] {
+			t.Fatalf("acting = %v, want %v", acting, entities[0])
+		}
+		if energy := tm.Energy()[entities[0]]; energy != 150 {
+			t.Fatalf("energy = %d, want 150", energy)
+		}
+	})
+
+	t.Run("entities not taking their turn aren't charged", func(t *testing.T) {
+		tm, entities := newTurnManager(normal, normal)
+		tm.Charge(entities[1], 10*ActionCost)
+
+		taken := takeTurns(tm, 4, nil)
+		want := []ecs.Entity{entities[1], entities[0], entities[1], entities[0]}
+		if !slices.Equal(taken, want) {
+			t.Fatalf("turns = %v, want %v", taken, want)
+		}
+	})
+}
+
+func TestEnergyIsKeptOutsideTheTurnOrder(t *testing.T) {
+	tm, entities := newTurnManager(normal, normal/2)
+	takeTurns(tm, 1, nil)
+	energy := tm.Energy()[entities[1]]
+	if energy == 0 {
+		t.Fatal("the slow entity has no energy to keep")
+	}
+
+	// The slow entity leaves with the level the player left, and comes back with it
+	tm.SetTurnOrder(entities[:1], 0)
+	takeTurns(tm, 3, nil)
+	tm.SetTurnOrder(entities, 0)
+	if got := tm.Energy()[entities[1]]; got != energy {
+		t.Fatalf("energy after coming back = %d, want %d", got, energy)
+	}
+
+	// It's kept through saving and loading too
+	saved := tm.Energy()
+	loaded, _ := newTurnManager(normal, normal/2)
+	loaded.SetTurnOrder(entities[:1], 0)
+	loaded.SetEnergy(saved)
+	loaded.SetTurnOrder(entities, 0)
+	if got := loaded.Energy()[entities[1]]; got != energy {
+		t.Fatalf("energy after loading = %d, want %d", got, energy)
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"cmp"
 	"fmt"
 	"log"
 	"slices"
@@ -54,7 +55,7 @@ func (m GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		currentEntity := m.game.GetCurrentEntity()
 
 		// Only process player input during player's turn
-		if m.game.HasComponent(currentEntity, components.PlayerControlled) {
+		if ecs.Has[components.PlayerControlledComponent](m.game.GetWorld(), currentEntity) {
 			switch msg.String() {
 			case "up", "k":
 				m.game.ProcessPlayerMove(0, -1)
@@ -86,6 +87,12 @@ func (m GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.game.RunAITurns()
 				return m, nil
 
+			case ">", "<":
+				m.game.ProcessPlayerStairs(msg.String() == ">")
+				m.game.RunPlayerTurn()
+				m.game.RunAITurns()
+				return m, nil
+
 			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
 				selectIndex := int(msg.String()[0] - '1') // Convert to 0-based index
 				usableEnts := m.game.GetPlayerUsableItems()
@@ -105,40 +112,39 @@ func (m GameModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m GameModel) View() string {
 	g := m.game
+	world := g.GetWorld()
 	width, height := g.GetWidth(), g.GetHeight()
 
-	// Create a grid with default "empty" characters
+	// Create a grid of the map's terrain
+	// Tiles out of view are drawn dimmed if the player has seen them, and not at all otherwise
+	tileMap := g.GetTileMap()
+	canSee := g.PlayerView()
 	tiles := make([][]string, height)
 	for y := range height {
 		tiles[y] = make([]string, width)
 		for x := range width {
-			tiles[y][x] = emptyChar
+			switch {
+			case canSee(x, y):
+				tiles[y][x] = renderTile(tileMap.At(x, y))
+			case tileMap.IsExplored(x, y):
+				tiles[y][x] = rememberedStyle.Render(string(tileMap.At(x, y).Glyph()))
+			default:
+				tiles[y][x] = " "
+			}
 		}
 	}
 
-	// Get all entities with position and sprite
-	entities := g.GetEntities()
-
-	// Place entities on the grid
-	for _, entity := range entities {
-		posComp, hasPos := g.GetComponent(entity, components.Position)
-		spriteComp, hasSprite := g.GetComponent(entity, components.Sprite)
-
-		if !hasPos || !hasSprite {
-			continue
-		}
-
-		pos := posComp.(*components.PositionComponent)
-		sprite := spriteComp.(*components.SpriteComponent)
-
-		// Make sure position is within bounds
-		if pos.X >= 0 && pos.X < width && pos.Y >= 0 && pos.Y < height {
+	// Place all entities with position and sprite that the player can see on the grid
+	drawables := ecs.Query2[components.PositionComponent, components.SpriteComponent](world)
+	for _, drawable := range drawables {
+		pos, sprite := drawable.A, drawable.B
+		if canSee(pos.X, pos.Y) {
 			tiles[pos.Y][pos.X] = string(sprite.Char)
 		}
 	}
 
 	// Build the game board string
-	board := titleStyle.Render(" Roguelike ECS Game ") + "\n\n"
+	board := titleStyle.Render(" Roguelike ECS Game ") + fmt.Sprintf(" Depth %d", g.GetDepth()) + "\n\n"
 
 	// Add border to the top
 	board += "┌"
@@ -169,38 +175,40 @@ func (m GameModel) View() string {
 	// Display entity health status
 	board += healthStyle.Render(" Health ") + "\n"
 
-	// Get a sorted list of entities
-	sortedEntities := make([]ecs.Entity, len(entities))
-	copy(sortedEntities, entities)
-	slices.Sort(sortedEntities)
+	// Get a sorted list of entities with health
+	creatures := ecs.Query1[components.HealthComponent](world)
+	slices.SortFunc(creatures, func(a, b ecs.Result1[components.HealthComponent]) int {
+		return cmp.Compare(a.Entity, b.Entity)
+	})
 
-	for _, entity := range sortedEntities {
-		healthComp, hasHealth := g.GetComponent(entity, components.Health)
-		if hasHealth {
-			health := healthComp.(*components.HealthComponent)
+	for _, creature := range creatures {
+		entity, health := creature.Entity, creature.A
+
+		// Only list creatures the player can see
+		pos, hasPos := ecs.Get[components.PositionComponent](world, entity)
+		if !hasPos || !canSee(pos.X, pos.Y) {
+			continue
+		}
 
-			var entityType string
-			if g.HasComponent(entity, components.PlayerControlled) {
-				entityType = "Player"
+		var entityType string
+		if ecs.Has[components.PlayerControlledComponent](world, entity) {
+			entityType = "Player"
+		} else {
+			sprite, hasSprite := ecs.Get[components.SpriteComponent](world, entity)
+			if hasSprite {
+				entityType = fmt.Sprintf("%c", sprite.Char)
 			} else {
-				spriteComp, hasSprite := g.GetComponent(entity, components.Sprite)
-				if hasSprite {
-					sprite := spriteComp.(*components.SpriteComponent)
-					entityType = fmt.Sprintf("%c", sprite.Char)
-				} else {
-					entityType = fmt.Sprintf("Enemy %d", entity)
-				}
+				entityType = fmt.Sprintf("Enemy %d", entity)
 			}
-
-			board += fmt.Sprintf("%s: HP %d/%d\n", entityType, health.HP, health.MaxHP)
 		}
+
+		board += fmt.Sprintf("%s: HP %d/%d\n", entityType, health.HP, health.MaxHP)
 	}
 
 	player := g.GetPlayerEntity()
 	if player != -1 {
-		if inventoryComp, hasInventory := g.GetComponent(player, components.Inventory); hasInventory {
+		if inventory, hasInventory := ecs.Get[components.InventoryComponent](world, player); hasInventory {
 			// Display inventory for player
-			inventory := inventoryComp.(*components.InventoryComponent)
 
 			board += "\n" + inventoryStyle.Render(" Quick Inventory ") + "\n"
 
@@ -209,8 +217,7 @@ func (m GameModel) View() string {
 			} else {
 				usableItems := g.GetPlayerUsableItems()
 				for i, itemEnt := range usableItems {
-					if itemComp, hasItem := g.GetComponent(itemEnt, components.Item); hasItem {
-						item := itemComp.(*components.ItemComponent)
+					if item, hasItem := ecs.Get[components.ItemComponent](world, itemEnt); hasItem {
 						board += fmt.Sprintf("%d) %s [%d gp] [%d lb]\n", i+1, item.Name, item.Value, item.Weight)
 					}
 				}
@@ -226,8 +233,7 @@ func (m GameModel) View() string {
 				for _, slot := range orderedEquipment {
 					itemString := fmt.Sprintf("%s: ", slot.Label)
 					if slot.Item != -1 {
-						if itemComp, hasItem := g.GetComponent(slot.Item, components.Item); hasItem {
-							item := itemComp.(*components.ItemComponent)
+						if item, hasItem := ecs.Get[components.ItemComponent](world, slot.Item); hasItem {
 							itemString += item.Name
 						}
 					} else {
@@ -243,7 +249,9 @@ func (m GameModel) View() string {
 	board += "\n" + infoStyle.Render(" Controls ") + "\n"
 	board += "Arrow keys: Move/Attack\n"
 	board += "Space: Pick up item\n"
+	board += "> / <: Take stairs down / up\n"
 	board += "1-9: Use inventory item\n"
+	board += "Shift+S / Shift+L: Save / Load game\n"
 	board += "Q: Quit game\n"
 
 	if g.GetIsGameOver() {
@@ -47,11 +47,14 @@ func (m InventoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// User can press (d) to drop an item
 	// User can press (esc) to close the inventory
 	// User can use arrow keys / jk to navigate the inventory
-	inventoryComp, hasInventory := m.game.GetComponent(m.game.GetPlayerEntity(), components.Inventory)
+	world := m.game.GetWorld()
+	inventory, hasInventory := ecs.Get[components.InventoryComponent](
+		world,
+		m.game.GetPlayerEntity(),
+	)
 	if !hasInventory {
 		return m, nil
 	}
-	inventory := inventoryComp.(*components.InventoryComponent)
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -93,7 +96,7 @@ func (m InventoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "u": // Use item
 			if m.sectionFocus == InventorySectionItems {
 				if itemEnt := inventory.Items[m.activeHover]; itemEnt != -1 {
-					if _, hasUsable := m.game.GetComponent(itemEnt, components.Usable); hasUsable {
+					if ecs.Has[components.UsableComponent](world, itemEnt) {
 						m.game.ProcessPlayerUseItem(itemEnt)
 						m.game.RunPlayerTurn()
 						m.game.RunAITurns()
@@ -116,7 +119,7 @@ func (m InventoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "e": // Equip item
 			if m.sectionFocus == InventorySectionItems {
 				if itemEnt := inventory.Items[m.activeHover]; itemEnt != -1 {
-					if _, hasEquippable := m.game.GetComponent(itemEnt, components.Equippable); hasEquippable {
+					if ecs.Has[components.EquippableComponent](world, itemEnt) {
 						m.game.ProcessPlayerEquipItem(itemEnt)
 						m.game.UpdateWorld()
 					}
@@ -136,18 +139,17 @@ func (m InventoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m InventoryModel) View() string {
 	screen := inventoryStyle.Render(" Inventory ") + "\n\n"
+	world := m.game.GetWorld()
 	player := m.game.GetPlayerEntity()
 	if player != -1 {
-		if inventoryComp, hasInventory := m.game.GetComponent(player, components.Inventory); hasInventory {
+		if inventory, hasInventory := ecs.Get[components.InventoryComponent](world, player); hasInventory {
 			// Display inventory for player
-			inventory := inventoryComp.(*components.InventoryComponent)
 
 			if len(inventory.Items) == 0 {
 				screen += "Empty\n"
 			} else {
 				for i, itemEnt := range inventory.Items {
-					if itemComp, hasItem := m.game.GetComponent(itemEnt, components.Item); hasItem {
-						item := itemComp.(*components.ItemComponent)
+					if item, hasItem := ecs.Get[components.ItemComponent](world, itemEnt); hasItem {
 						itemString := fmt.Sprintf("%d) %s [%d gp] [%d lb]", i+1, item.Name, item.Value, item.Weight)
 						if i == m.activeHover && m.sectionFocus == InventorySectionItems {
 							screen += itemHoverStyle.Render(itemString) + "\n"
@@ -168,8 +170,7 @@ func (m InventoryModel) View() string {
 				for i, slot := range orderedEquipment {
 					itemString := fmt.Sprintf("%s: ", slot.Label)
 					if slot.Item != -1 {
-						if itemComp, hasItem := m.game.GetComponent(slot.Item, components.Item); hasItem {
-							item := itemComp.(*components.ItemComponent)
+						if item, hasItem := ecs.Get[components.ItemComponent](world, slot.Item); hasItem {
 							itemString += item.Name
 						}
 					} else {
@@ -206,11 +207,12 @@ func (m InventoryModel) slotsToSlice(slots map[components.EquipmentSlot]ecs.Enti
 }
 
 func (m InventoryModel) getControlsForItem(itemEnt ecs.Entity) string {
+	world := m.game.GetWorld()
 	controls := ""
-	if _, hasUsable := m.game.GetComponent(itemEnt, components.Usable); hasUsable {
+	if ecs.Has[components.UsableComponent](world, itemEnt) {
 		controls += "Use (u)\n"
 	}
-	if _, hasEquippable := m.game.GetComponent(itemEnt, components.Equippable); hasEquippable {
+	if ecs.Has[components.EquippableComponent](world, itemEnt) {
 		controls += "Equip (e)\n"
 	}
 	controls += "Drop (d)\n"
@@ -230,12 +232,11 @@ func (m InventoryModel) getControls() string {
 			return controls
 		}
 
-		inventoryComp, hasInventory := m.game.GetComponent(player, components.Inventory)
+		inventory, hasInventory := ecs.Get[components.InventoryComponent](m.game.GetWorld(), player)
 		if !hasInventory {
 			return controls
 		}
 
-		inventory := inventoryComp.(*components.InventoryComponent)
 		if m.activeHover < len(inventory.Items) {
 			controls += m.getControlsForItem(inventory.Items[m.activeHover])
 		}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"log"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -48,6 +49,18 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else if msg.String() == "esc" && m.activeScreen != GameScreen {
 			m.activeScreen = GameScreen
 			return m, nil
+		} else if msg.String() == "S" && m.activeScreen == GameScreen {
+			m.game.SaveGame(game.SavePath)
+			return m, nil
+		} else if msg.String() == "L" && m.activeScreen == GameScreen {
+			// The loaded game replaces the current one on every screen
+			loaded, err := game.LoadFromFile(game.SavePath, m.logger, m.game.GetContentDir())
+			if err != nil {
+				m.game.SetStatusMessage(fmt.Sprintf("Could not load game: %v", err))
+				return m, nil
+			}
+			loaded.SetStatusMessage("Game loaded")
+			return NewMainModel(loaded, m.logger), nil
 		}
 	}
 
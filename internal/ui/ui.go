@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"ecs/internal/game"
+	"ecs/internal/game/resources"
 )
 
 var (
@@ -42,12 +43,35 @@ var (
 			Background(lipgloss.Color("#7D56F4")).
 			Underline(true)
 
-	emptyChar = "·" // Using a middle dot for empty space
+	wallStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	doorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#AA7733"))
+	waterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3366FF"))
+	grassStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#33AA33"))
+	stairStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFDD33")).Bold(true)
+
+	rememberedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#444444"))
 )
 
-func RunGame(g *game.Game, logger *log.Logger) {
-	g.Initialize()
+// renderTile draws a map tile in the color of its terrain
+func renderTile(tile resources.Tile) string {
+	glyph := string(tile.Glyph())
+	switch tile {
+	case resources.Wall:
+		return wallStyle.Render(glyph)
+	case resources.Door, resources.ClosedDoor:
+		return doorStyle.Render(glyph)
+	case resources.Water:
+		return waterStyle.Render(glyph)
+	case resources.Grass:
+		return grassStyle.Render(glyph)
+	case resources.StairsDown, resources.StairsUp:
+		return stairStyle.Render(glyph)
+	}
+	return glyph
+}
 
+// RunGame runs the TUI for a game that is either initialized or loaded
+func RunGame(g *game.Game, logger *log.Logger) {
 	p := tea.NewProgram(
 		NewMainModel(g, logger),
 		tea.WithAltScreen(),
@@ -0,0 +1,60 @@
+package ecs
+
+// Commands queues structural changes to the world (spawning and despawning
+// entities, adding and removing components) so systems can request them while
+// iterating, and have them applied at the next sync point instead
+type Commands struct {
+	queue []func(w *World)
+}
+
+// Spawn queues the creation of a new entity with the given components
+func (c *Commands) Spawn(components ...Component) {
+	c.queue = append(c.queue, func(w *World) {
+		entity := w.EntityManager.CreateEntity()
+		addComponents(w, entity, components)
+	})
+}
+
+// Despawn queues the removal of the entity and all of its components
+func (c *Commands) Despawn(entity Entity) {
+	c.queue = append(c.queue, func(w *World) {
+		w.RemoveEntity(entity)
+	})
+}
+
+// Add queues attaching the components to the entity, replacing any of the same type
+func (c *Commands) Add(entity Entity, components ...Component) {
+	c.queue = append(c.queue, func(w *World) {
+		addComponents(w, entity, components)
+	})
+}
+
+// Remove queues detaching the given component types from the entity
+func (c *Commands) Remove(entity Entity, componentTypes ...ComponentType) {
+	c.queue = append(c.queue, func(w *World) {
+		for _, componentType := range componentTypes {
+			w.ComponentManager.RemoveComponent(entity, componentType)
+		}
+	})
+}
+
+// apply runs every queued command in the order it was queued
+// Commands queued while applying are run as well
+func (c *Commands) apply(w *World) {
+	for len(c.queue) > 0 {
+		queue := c.queue
+		c.queue = nil
+		for _, command := range queue {
+			command(w)
+		}
+	}
+}
+
+func addComponents(w *World, entity Entity, components []Component) {
+	if !w.IsAlive(entity) {
+		return
+	}
+	for _, component := range components {
+		w.ComponentManager.AddComponent(entity, TypeOfComponent(component), component)
+	}
+}
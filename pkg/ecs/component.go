@@ -1,5 +1,10 @@
 package ecs
 
+import (
+	"reflect"
+	"sync"
+)
+
 // Component is a marker interface for all component types
 type Component interface {
 	IsComponent()
@@ -7,23 +12,163 @@ type Component interface {
 
 type ComponentType string
 
+// componentTypeCache maps a Go type to the ComponentType key derived from it
+var componentTypeCache sync.Map
+
+// typeRegistry maps the name of a component, resource or event type back to its
+// Go type, so snapshots can rebuild values from their name. Every type passed to
+// TypeOf, SetResource, Emit or Subscribe is registered
+var typeRegistry sync.Map
+
+// TypeOf returns the ComponentType key for the component struct T
+// The key is derived from the type itself (ie. "ecs/internal/game/components.PositionComponent"),
+// so the generic API and the string-keyed API share the same storage
+func TypeOf[T any]() ComponentType {
+	return componentTypeFor(reflect.TypeFor[T]())
+}
+
+// TypeOfComponent returns the ComponentType key for a component value,
+// matching TypeOf for both T and *T
+func TypeOfComponent(component Component) ComponentType {
+	t := reflect.TypeOf(component)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return componentTypeFor(t)
+}
+
+func componentTypeFor(t reflect.Type) ComponentType {
+	if componentType, ok := componentTypeCache.Load(t); ok {
+		return componentType.(ComponentType)
+	}
+
+	componentType := ComponentType(typeName(t))
+	componentTypeCache.Store(t, componentType)
+	registerType(t)
+	return componentType
+}
+
+// typeName returns the name a type is keyed and registered under
+// Named types are qualified by their full package path, so types of the same name in
+// packages of the same name don't collide
+func typeName(t reflect.Type) string {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+// registerType records the Go type under its name in the type registry
+func registerType(t reflect.Type) string {
+	name := typeName(t)
+	if _, found := typeRegistry.Load(name); !found {
+		typeRegistry.Store(name, t)
+	}
+	return name
+}
+
+// lookupType returns the Go type registered under the name
+func lookupType(name string) (reflect.Type, bool) {
+	t, found := typeRegistry.Load(name)
+	if !found {
+		return nil, false
+	}
+	return t.(reflect.Type), true
+}
+
+// lookupComponentType returns the Go type registered for the ComponentType key
+func lookupComponentType(componentType ComponentType) (reflect.Type, bool) {
+	return lookupType(string(componentType))
+}
+
+// isComponentType reports whether the key is for a component, rather than ie. a resource
+// declared with Reads or Writes
+func isComponentType(componentType ComponentType) bool {
+	t, registered := lookupComponentType(componentType)
+	return registered && reflect.PointerTo(t).Implements(reflect.TypeFor[Component]())
+}
+
 // ComponentManager handles storage and retrieval of components
 type ComponentManager struct {
-	components map[ComponentType]map[Entity]Component
+	components     map[ComponentType]componentStorage
+	componentTypes []ComponentType // Registration order, so iteration is deterministic
+	observers      map[ComponentType]*componentObservers
+	storageKind    StorageKind
+
+	// changeTick is stamped on every add, replace, change and removal of a component,
+	// and advanced by the world after each batch of systems runs
+	changeTick uint64
+}
+
+// componentObservers holds the hooks registered for one component type
+type componentObservers struct {
+	onAdd     []func(entity Entity, component Component)
+	onRemove  []func(entity Entity, component Component)
+	onReplace []func(entity Entity, old, new Component)
 }
 
 func NewComponentManager() *ComponentManager {
+	return NewComponentManagerWithStorage(SparseSetStorage)
+}
+
+// NewComponentManagerWithStorage creates a ComponentManager that stores every
+// component type in the given kind of storage
+func NewComponentManagerWithStorage(storageKind StorageKind) *ComponentManager {
 	return &ComponentManager{
-		components: make(map[ComponentType]map[Entity]Component),
+		components:     make(map[ComponentType]componentStorage),
+		componentTypes: []ComponentType{},
+		observers:      make(map[ComponentType]*componentObservers),
+		storageKind:    storageKind,
+		changeTick:     1,
 	}
 }
 
 func (cm *ComponentManager) RegisterComponentType(componentType ComponentType) {
 	if _, exists := cm.components[componentType]; !exists {
-		cm.components[componentType] = make(map[Entity]Component)
+		cm.components[componentType] = newComponentStorage(cm.storageKind)
+		cm.componentTypes = append(cm.componentTypes, componentType)
 	}
 }
 
+// OnAdd registers a hook that runs after a component of the given type is
+// attached to an entity that didn't have one
+func (cm *ComponentManager) OnAdd(
+	componentType ComponentType,
+	hook func(entity Entity, component Component),
+) {
+	observers := cm.observersFor(componentType)
+	observers.onAdd = append(observers.onAdd, hook)
+}
+
+// OnRemove registers a hook that runs after a component of the given type is
+// detached from an entity, including when the entity is removed
+func (cm *ComponentManager) OnRemove(
+	componentType ComponentType,
+	hook func(entity Entity, component Component),
+) {
+	observers := cm.observersFor(componentType)
+	observers.onRemove = append(observers.onRemove, hook)
+}
+
+// OnReplace registers a hook that runs after a component of the given type is
+// attached to an entity that already had one
+func (cm *ComponentManager) OnReplace(
+	componentType ComponentType,
+	hook func(entity Entity, old, new Component),
+) {
+	observers := cm.observersFor(componentType)
+	observers.onReplace = append(observers.onReplace, hook)
+}
+
+func (cm *ComponentManager) observersFor(componentType ComponentType) *componentObservers {
+	observers, exists := cm.observers[componentType]
+	if !exists {
+		observers = &componentObservers{}
+		cm.observers[componentType] = observers
+	}
+	return observers
+}
+
 func (cm *ComponentManager) AddComponent(
 	entity Entity,
 	componentType ComponentType,
@@ -32,12 +177,49 @@ func (cm *ComponentManager) AddComponent(
 	if _, exists := cm.components[componentType]; !exists {
 		cm.RegisterComponentType(componentType)
 	}
-	cm.components[componentType][entity] = component
+	storage := cm.components[componentType]
+	old, replaced := storage.get(entity)
+
+	// A stale entity handle may be rejected by the storage
+	if !storage.set(entity, component, cm.changeTick) {
+		return
+	}
+
+	if observers, exists := cm.observers[componentType]; exists {
+		if replaced {
+			for _, hook := range observers.onReplace {
+				hook(entity, old, component)
+			}
+		} else {
+			for _, hook := range observers.onAdd {
+				hook(entity, component)
+			}
+		}
+	}
 }
 
 func (cm *ComponentManager) RemoveComponent(entity Entity, componentType ComponentType) {
-	if componentMap, exists := cm.components[componentType]; exists {
-		delete(componentMap, entity)
+	if storage, exists := cm.components[componentType]; exists {
+		cm.removeFrom(storage, entity, componentType)
+	}
+}
+
+func (cm *ComponentManager) removeFrom(
+	storage componentStorage,
+	entity Entity,
+	componentType ComponentType,
+) {
+	component, found := storage.get(entity)
+	if !found {
+		return
+	}
+	storage.remove(entity)
+	storage.logRemoval(entity, cm.changeTick)
+
+	if observers, exists := cm.observers[componentType]; exists {
+		for _, hook := range observers.onRemove {
+			hook(entity, component)
+		}
 	}
 }
 
@@ -45,34 +227,68 @@ func (cm *ComponentManager) GetComponent(
 	entity Entity,
 	componentType ComponentType,
 ) (Component, bool) {
-	if componentMap, exists := cm.components[componentType]; exists {
-		component, found := componentMap[entity]
-		return component, found
+	if storage, exists := cm.components[componentType]; exists {
+		return storage.get(entity)
 	}
 	return nil, false
 }
 
 func (cm *ComponentManager) HasComponent(entity Entity, componentType ComponentType) bool {
-	if componentMap, exists := cm.components[componentType]; exists {
-		_, found := componentMap[entity]
-		return found
+	if storage, exists := cm.components[componentType]; exists {
+		return storage.has(entity)
 	}
 	return false
 }
 
 func (cm *ComponentManager) GetAllEntitiesWithComponent(componentType ComponentType) []Entity {
-	if componentMap, exists := cm.components[componentType]; exists {
-		entities := make([]Entity, 0, len(componentMap))
-		for e := range componentMap {
-			entities = append(entities, e)
-		}
-		return entities
+	if storage, exists := cm.components[componentType]; exists {
+		return storage.entities()
 	}
 	return []Entity{}
 }
 
 func (cm *ComponentManager) RemoveAllComponents(entity Entity) {
-	for _, componentMap := range cm.components {
-		delete(componentMap, entity)
+	for _, componentType := range cm.componentTypes {
+		cm.removeFrom(cm.components[componentType], entity, componentType)
+	}
+}
+
+// MarkChanged records that the entity's component of the given type was modified in place
+func (cm *ComponentManager) MarkChanged(entity Entity, componentType ComponentType) {
+	if storage, exists := cm.components[componentType]; exists {
+		storage.markChanged(entity, cm.changeTick)
+	}
+}
+
+func (cm *ComponentManager) ticks(
+	entity Entity,
+	componentType ComponentType,
+) (componentTicks, bool) {
+	if storage, exists := cm.components[componentType]; exists {
+		return storage.ticks(entity)
+	}
+	return componentTicks{}, false
+}
+
+// removedSince returns the entities that had a component of the given type
+// detached after the tick, in the order they were detached
+func (cm *ComponentManager) removedSince(componentType ComponentType, tick uint64) []Entity {
+	if storage, exists := cm.components[componentType]; exists {
+		return storage.removedSince(tick)
+	}
+	return nil
+}
+
+// pruneRemovals forgets removals that happened at or before the tick
+func (cm *ComponentManager) pruneRemovals(tick uint64) {
+	for _, storage := range cm.components {
+		storage.pruneRemovals(tick)
+	}
+}
+
+func (cm *ComponentManager) count(componentType ComponentType) int {
+	if storage, exists := cm.components[componentType]; exists {
+		return storage.len()
 	}
+	return 0
 }
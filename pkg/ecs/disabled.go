@@ -0,0 +1,31 @@
+package ecs
+
+// Disabled hides an entity from queries without removing it, ie. while it is on a level
+// that isn't being played. Its components and relations are kept as they are, and it is
+// still saved in snapshots
+// Queries and EntitiesWith only match disabled entities when they ask for Disabled itself
+type Disabled struct{}
+
+func (d Disabled) IsComponent() {}
+
+var disabledType = TypeOf[Disabled]()
+
+// Disable hides the entity from queries until it is enabled again
+func Disable(w *World, entity Entity) {
+	Add(w, entity, &Disabled{})
+}
+
+// Enable makes a disabled entity show up in queries again
+func Enable(w *World, entity Entity) {
+	Remove[Disabled](w, entity)
+}
+
+// IsDisabled reports whether the entity is hidden from queries
+func IsDisabled(w *World, entity Entity) bool {
+	return w.ComponentManager.HasComponent(entity, disabledType)
+}
+
+// enabled is the filter every query gets, unless it asks for Disabled
+func enabled(w *World, entity Entity) bool {
+	return !IsDisabled(w, entity)
+}
@@ -1,41 +1,93 @@
 package ecs
 
 // Entity is just an identifier for game objects
-type Entity int
+// The low bits hold an index that may be recycled once the entity is removed,
+// and the high bits hold the generation of that index, so handles to a removed
+// entity never match the entity that reuses its index
+// It is 64 bits wide on every platform so both halves fit, and -1 means no entity
+type Entity int64
+
+const (
+	entityIndexBits = 32
+	entityIndexMask = 1<<entityIndexBits - 1
+)
+
+func newEntity(index, generation int) Entity {
+	return Entity(int64(generation)<<entityIndexBits | int64(index))
+}
+
+// Index returns the slot of the entity, which is shared with any recycled entity
+func (e Entity) Index() int {
+	return int(e & entityIndexMask)
+}
+
+// Generation returns how many times the entity's index had been recycled when it was created
+func (e Entity) Generation() int {
+	return int(e >> entityIndexBits)
+}
 
 // EntityManager handles entity creation and removal
 type EntityManager struct {
-	nextEntityID Entity
-	entities     map[Entity]struct{}
+	generations []int  // Current generation of each index
+	alive       []bool // Whether the current generation of each index is alive
+	freeIndices []int  // Indices of removed entities, ready to be recycled
 }
 
 func NewEntityManager() *EntityManager {
+	// Index 0 is never handed out, so entity IDs start at 1
 	return &EntityManager{
-		nextEntityID: 1,
-		entities:     make(map[Entity]struct{}),
+		generations: []int{0},
+		alive:       []bool{false},
+		freeIndices: []int{},
 	}
 }
 
 func (em *EntityManager) CreateEntity() Entity {
-	e := em.nextEntityID
-	em.entities[e] = struct{}{}
-	em.nextEntityID++
-	return e
+	if len(em.freeIndices) > 0 {
+		index := em.freeIndices[0]
+		em.freeIndices = em.freeIndices[1:]
+		em.alive[index] = true
+		return newEntity(index, em.generations[index])
+	}
+
+	index := len(em.generations)
+	em.generations = append(em.generations, 0)
+	em.alive = append(em.alive, true)
+	return newEntity(index, 0)
 }
 
 func (em *EntityManager) RemoveEntity(entity Entity) {
-	delete(em.entities, entity)
+	if !em.IsAlive(entity) {
+		return
+	}
+
+	index := entity.Index()
+	em.alive[index] = false
+	em.generations[index]++
+	em.freeIndices = append(em.freeIndices, index)
+}
+
+// IsAlive reports whether the entity exists, rejecting stale handles to removed entities
+func (em *EntityManager) IsAlive(entity Entity) bool {
+	if entity < 0 {
+		return false
+	}
+	index := entity.Index()
+	return index < len(em.generations) &&
+		em.alive[index] &&
+		em.generations[index] == entity.Generation()
 }
 
 func (em *EntityManager) HasEntity(entity Entity) bool {
-	_, exists := em.entities[entity]
-	return exists
+	return em.IsAlive(entity)
 }
 
 func (em *EntityManager) GetAllEntities() []Entity {
-	entities := make([]Entity, 0, len(em.entities))
-	for e := range em.entities {
-		entities = append(entities, e)
+	entities := []Entity{}
+	for index, alive := range em.alive {
+		if alive {
+			entities = append(entities, newEntity(index, em.generations[index]))
+		}
 	}
 	return entities
 }
@@ -0,0 +1,33 @@
+package ecs
+
+import "testing"
+
+func TestRecycledEntityGetsNewGeneration(t *testing.T) {
+	em := NewEntityManager()
+	first := em.CreateEntity()
+	em.RemoveEntity(first)
+	recycled := em.CreateEntity()
+
+	if recycled.Index() != first.Index() {
+		t.Fatalf("recycled index = %d, want %d", recycled.Index(), first.Index())
+	}
+	if recycled.Generation() != first.Generation()+1 {
+		t.Fatalf("recycled generation = %d, want %d", recycled.Generation(), first.Generation()+1)
+	}
+	if em.IsAlive(first) {
+		t.Fatal("stale handle to a removed entity is alive")
+	}
+	if !em.IsAlive(recycled) {
+		t.Fatal("recycled entity isn't alive")
+	}
+}
+
+func TestEntityKeepsHighGenerations(t *testing.T) {
+	entity := newEntity(7, 1<<20)
+	if entity.Index() != 7 || entity.Generation() != 1<<20 {
+		t.Fatalf(
+			"entity = index %d generation %d, want 7 and %d",
+			entity.Index(), entity.Generation(), 1<<20,
+		)
+	}
+}
@@ -0,0 +1,36 @@
+package ecs
+
+import "reflect"
+
+// Events are plain Go structs, keyed by their type
+// They are queued while systems run, and delivered to subscribers after every
+// system has updated
+
+// Emit queues the event for delivery to every subscriber of its type
+func Emit[T any](w *World, event T) {
+	registerType(reflect.TypeFor[T]())
+	w.eventQueue = append(w.eventQueue, event)
+}
+
+// Subscribe registers a handler that receives every event of type T
+func Subscribe[T any](w *World, handler func(T)) {
+	eventType := reflect.TypeFor[T]()
+	registerType(eventType)
+	w.eventHandlers[eventType] = append(w.eventHandlers[eventType], func(event any) {
+		handler(event.(T))
+	})
+}
+
+func (w *World) processEvents() {
+	// Handlers may emit events of their own, so keep going until the queue is drained
+	for len(w.eventQueue) > 0 {
+		queue := w.eventQueue
+		w.eventQueue = nil
+
+		for _, event := range queue {
+			for _, handler := range w.eventHandlers[reflect.TypeOf(event)] {
+				handler(event)
+			}
+		}
+	}
+}
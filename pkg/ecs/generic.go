@@ -0,0 +1,82 @@
+package ecs
+
+// componentPtr constrains PT to be a pointer to T that implements Component,
+// which lets Add infer T from the component value passed in
+type componentPtr[T any] interface {
+	*T
+	Component
+}
+
+// Get returns the component of type T attached to the entity
+func Get[T any](w *World, entity Entity) (*T, bool) {
+	component, found := w.ComponentManager.GetComponent(entity, TypeOf[T]())
+	if !found {
+		return nil, false
+	}
+	typed, ok := any(component).(*T)
+	return typed, ok
+}
+
+// GetMut returns the component of type T attached to the entity, and marks it as
+// changed so Changed filters pick it up
+func GetMut[T any](w *World, entity Entity) (*T, bool) {
+	component, found := Get[T](w, entity)
+	if found {
+		w.ComponentManager.MarkChanged(entity, TypeOf[T]())
+	}
+	return component, found
+}
+
+// MarkChanged marks the entity's component of type T as modified in place
+func MarkChanged[T any](w *World, entity Entity) {
+	w.ComponentManager.MarkChanged(entity, TypeOf[T]())
+}
+
+// Add attaches the component to the entity, replacing any existing component of the same type
+// Components are never attached to entities that are no longer alive
+func Add[T any, PT componentPtr[T]](w *World, entity Entity, component PT) {
+	if !w.IsAlive(entity) {
+		return
+	}
+	w.ComponentManager.AddComponent(entity, TypeOf[T](), component)
+}
+
+// Has reports whether the entity has a component of type T
+func Has[T any](w *World, entity Entity) bool {
+	return w.ComponentManager.HasComponent(entity, TypeOf[T]())
+}
+
+// Remove detaches the component of type T from the entity
+func Remove[T any](w *World, entity Entity) {
+	w.ComponentManager.RemoveComponent(entity, TypeOf[T]())
+}
+
+// EntitiesWith returns all entities that have a component of type T,
+// leaving out disabled entities unless T is Disabled
+func EntitiesWith[T any](w *World) []Entity {
+	return w.Query([]ComponentType{TypeOf[T]()})
+}
+
+// OnAdd registers a hook that runs after a component of type T is attached to
+// an entity that didn't have one
+func OnAdd[T any](w *World, hook func(entity Entity, component *T)) {
+	w.ComponentManager.OnAdd(TypeOf[T](), func(entity Entity, component Component) {
+		hook(entity, any(component).(*T))
+	})
+}
+
+// OnRemove registers a hook that runs after a component of type T is detached
+// from an entity, including when the entity is removed
+func OnRemove[T any](w *World, hook func(entity Entity, component *T)) {
+	w.ComponentManager.OnRemove(TypeOf[T](), func(entity Entity, component Component) {
+		hook(entity, any(component).(*T))
+	})
+}
+
+// OnReplace registers a hook that runs after a component of type T is attached
+// to an entity that already had one
+func OnReplace[T any](w *World, hook func(entity Entity, old, new *T)) {
+	w.ComponentManager.OnReplace(TypeOf[T](), func(entity Entity, old, new Component) {
+		hook(entity, any(old).(*T), any(new).(*T))
+	})
+}
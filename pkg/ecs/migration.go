@@ -0,0 +1,100 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Component types start at schema version 1, and move up a version for every
+// migration registered for them. Binary snapshots record the version each type was
+// saved at, and run the migrations needed to bring older components up to date
+
+// componentMigration upgrades a component from one schema version to the next
+type componentMigration struct {
+	oldType reflect.Type // Layout of the component at the version being migrated from
+	newType reflect.Type // Layout at the next version
+	migrate func(old any) any
+}
+
+var (
+	migrationsMu sync.RWMutex
+	migrations   = map[ComponentType]map[int]componentMigration{}
+)
+
+// RegisterMigration registers how to upgrade a component of the given type saved at
+// schema version `from` (decoded into Old) to version from+1 (New)
+// New is the component struct itself for the latest migration, and the next
+// migration's Old otherwise
+func RegisterMigration[Old, New any](
+	componentType ComponentType,
+	from int,
+	migrate func(old *Old) *New,
+) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+
+	if migrations[componentType] == nil {
+		migrations[componentType] = map[int]componentMigration{}
+	}
+	migrations[componentType][from] = componentMigration{
+		oldType: reflect.TypeFor[Old](),
+		newType: reflect.TypeFor[New](),
+		migrate: func(old any) any {
+			return migrate(old.(*Old))
+		},
+	}
+}
+
+// SchemaVersion returns the current schema version of the component type
+func SchemaVersion(componentType ComponentType) int {
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	version := 1
+	for from := range migrations[componentType] {
+		version = max(version, from+1)
+	}
+	return version
+}
+
+// migrationPath returns the migrations that bring a component type from the saved
+// version up to the current one, checking that each step's layouts line up
+func migrationPath(componentType ComponentType, saved int) ([]componentMigration, error) {
+	current := SchemaVersion(componentType)
+	if saved > current {
+		return nil, fmt.Errorf(
+			"ecs: %s was saved at schema version %d, newer than the current version %d",
+			componentType, saved, current,
+		)
+	}
+
+	migrationsMu.RLock()
+	defer migrationsMu.RUnlock()
+
+	path := []componentMigration{}
+	for version := saved; version < current; version++ {
+		migration, found := migrations[componentType][version]
+		if !found {
+			return nil, fmt.Errorf("ecs: no migration for %s from schema version %d", componentType, version)
+		}
+		if len(path) > 0 && path[len(path)-1].newType != migration.oldType {
+			return nil, fmt.Errorf(
+				"ecs: migration for %s from schema version %d expects %s, but the previous one produces %s",
+				componentType, version, migration.oldType, path[len(path)-1].newType,
+			)
+		}
+		path = append(path, migration)
+	}
+
+	if len(path) > 0 {
+		latest, _ := lookupComponentType(componentType)
+		if last := path[len(path)-1]; last.newType != latest {
+			return nil, fmt.Errorf(
+				"ecs: latest migration for %s produces %s instead of %s",
+				componentType, last.newType, latest,
+			)
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,188 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+)
+
+// testArmor is at schema version 3
+// Version 1 had a single rating, version 2 split it into defense and weight, and
+// version 3 named the material it is made of
+type testArmor struct {
+	Defense  int
+	Weight   int
+	Material string
+}
+
+func (testArmor) IsComponent() {}
+
+type testArmorV1 struct {
+	Rating int
+}
+
+type testArmorV2 struct {
+	Defense int
+	Weight  int
+}
+
+// testBoots skips a migration, from version 2 to 3
+type testBoots struct{ Size int }
+
+func (testBoots) IsComponent() {}
+
+// testGloves has migrations that don't line up, version 2 is decoded as two types
+type testGloves struct{ Grip int }
+
+func (testGloves) IsComponent() {}
+
+type testGlovesV2 struct{ Grip int }
+
+type testGlovesV2Other struct{ Grip string }
+
+func registerTestMigrations() {
+	RegisterMigration(TypeOf[testArmor](), 1, func(old *testArmorV1) *testArmorV2 {
+		return &testArmorV2{Defense: old.Rating, Weight: old.Rating / 2}
+	})
+	RegisterMigration(TypeOf[testArmor](), 2, func(old *testArmorV2) *testArmor {
+		return &testArmor{Defense: old.Defense, Weight: old.Weight, Material: "leather"}
+	})
+
+	RegisterMigration(TypeOf[testBoots](), 1, func(old *testBoots) *testBoots { return old })
+	RegisterMigration(TypeOf[testBoots](), 3, func(old *testBoots) *testBoots { return old })
+
+	RegisterMigration(TypeOf[testGloves](), 1, func(old *testGloves) *testGlovesV2 {
+		return &testGlovesV2{Grip: old.Grip}
+	})
+	RegisterMigration(TypeOf[testGloves](), 2, func(old *testGlovesV2Other) *testGloves {
+		return &testGloves{}
+	})
+}
+
+// saveAtVersion saves the world with the components of the type saved at an older
+// schema version, encoded from the values in entity order
+func saveAtVersion[T Component](t *testing.T, w *World, version int, values ...any) []byte {
+	t.Helper()
+	return rewriteSnapshot(t, saveBinary(t, w), func(snapshot *binarySnapshot) {
+		saved := column(t, snapshot, TypeOf[T]())
+		var data bytes.Buffer
+		encoder := gob.NewEncoder(&data)
+		for _, value := range values {
+			if err := encoder.Encode(value); err != nil {
+				t.Fatalf("encoding %T: %v", value, err)
+			}
+		}
+		saved.Version, saved.Data = version, data.Bytes()
+	})
+}
+
+func TestMigrationsRunInOrder(t *testing.T) {
+	registerTestMigrations()
+	if version := SchemaVersion(TypeOf[testArmor]()); version != 3 {
+		t.Fatalf("schema version = %d, want 3", version)
+	}
+
+	w := newTestWorld()
+	helmet := w.EntityManager.CreateEntity()
+	chestpiece := w.EntityManager.CreateEntity()
+	Add(w, helmet, &testArmor{})
+	Add(w, chestpiece, &testArmor{})
+
+	tests := []struct {
+		name    string
+		version int
+		values  []any
+		want    []testArmor
+	}{
+		{
+			name:    "from version 1",
+			version: 1,
+			values:  []any{&testArmorV1{Rating: 4}, &testArmorV1{Rating: 10}},
+			want: []testArmor{
+				{Defense: 4, Weight: 2, Material: "leather"},
+				{Defense: 10, Weight: 5, Material: "leather"},
+			},
+		},
+		{
+			name:    "from version 2",
+			version: 2,
+			values:  []any{&testArmorV2{Defense: 4, Weight: 1}, &testArmorV2{Defense: 10}},
+			want: []testArmor{
+				{Defense: 4, Weight: 1, Material: "leather"},
+				{Defense: 10, Weight: 0, Material: "leather"},
+			},
+		},
+		{
+			name:    "current version",
+			version: 3,
+			values: []any{
+				&testArmor{Defense: 4, Weight: 1, Material: "iron"},
+				&testArmor{Defense: 10, Weight: 2, Material: "steel"},
+			},
+			want: []testArmor{
+				{Defense: 4, Weight: 1, Material: "iron"},
+				{Defense: 10, Weight: 2, Material: "steel"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := saveAtVersion[testArmor](t, w, test.version, test.values...)
+			loaded := newTestWorld()
+			if err := loaded.LoadBinary(bytes.NewReader(data)); err != nil {
+				t.Fatalf("loading: %v", err)
+			}
+			for i, entity := range []Entity{helmet, chestpiece} {
+				if armor, _ := Get[testArmor](loaded, entity); *armor != test.want[i] {
+					t.Fatalf("armor of entity %d = %+v, want %+v", entity, *armor, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMigrationErrors(t *testing.T) {
+	registerTestMigrations()
+
+	tests := []struct {
+		name string
+		save func(t *testing.T, w *World) []byte
+		err  string
+	}{
+		{
+			name: "saved by a newer version",
+			save: func(t *testing.T, w *World) []byte {
+				Add(w, w.EntityManager.CreateEntity(), &testArmor{})
+				return saveAtVersion[testArmor](t, w, 4, &testArmor{})
+			},
+			err: "saved at schema version 4, newer than the current version 3",
+		},
+		{
+			name: "missing step",
+			save: func(t *testing.T, w *World) []byte {
+				Add(w, w.EntityManager.CreateEntity(), &testBoots{})
+				return saveAtVersion[testBoots](t, w, 1, &testBoots{})
+			},
+			err: "no migration for " + string(TypeOf[testBoots]()) + " from schema version 2",
+		},
+		{
+			name: "steps don't line up",
+			save: func(t *testing.T, w *World) []byte {
+				Add(w, w.EntityManager.CreateEntity(), &testGloves{})
+				return saveAtVersion[testGloves](t, w, 1, &testGloves{})
+			},
+			err: "from schema version 2 expects ecs.testGlovesV2Other, " +
+				"but the previous one produces ecs.testGlovesV2",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := test.save(t, newTestWorld())
+			err := newTestWorld().LoadBinary(bytes.NewReader(data))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("error = %v, want one containing %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Prefab is a named template for spawning entities
+// Each constructor builds a fresh component, so spawned entities never share state
+// A prefab that Extends another starts with its parent's components, and replaces
+// any of the same type with its own
+type Prefab struct {
+	Name       string
+	Extends    string
+	Components []func() Component
+}
+
+// PrefabRegistry holds the prefabs that can be spawned by name
+type PrefabRegistry struct {
+	prefabs map[string]Prefab
+	names   []string // Registration order
+}
+
+func NewPrefabRegistry() *PrefabRegistry {
+	return &PrefabRegistry{
+		prefabs: make(map[string]Prefab),
+		names:   []string{},
+	}
+}
+
+// Register adds the prefab, which may extend a prefab that isn't registered yet
+func (pr *PrefabRegistry) Register(prefab Prefab) error {
+	if prefab.Name == "" {
+		return fmt.Errorf("ecs: prefab has no name")
+	}
+	if _, exists := pr.prefabs[prefab.Name]; exists {
+		return fmt.Errorf("ecs: duplicate prefab %q", prefab.Name)
+	}
+	pr.prefabs[prefab.Name] = prefab
+	pr.names = append(pr.names, prefab.Name)
+	return nil
+}
+
+// Has reports whether a prefab with the name is registered
+func (pr *PrefabRegistry) Has(name string) bool {
+	_, exists := pr.prefabs[name]
+	return exists
+}
+
+// Names returns the names of every registered prefab, in registration order
+func (pr *PrefabRegistry) Names() []string {
+	return slices.Clone(pr.names)
+}
+
+// Components builds the components of the prefab, including the ones it inherits
+// Inherited components come first, in the order their prefab lists them
+func (pr *PrefabRegistry) Components(name string) ([]Component, error) {
+	return pr.build(name, nil)
+}
+
+func (pr *PrefabRegistry) build(name string, visiting []string) ([]Component, error) {
+	if slices.Contains(visiting, name) {
+		return nil, fmt.Errorf(
+			"ecs: prefab %q extends itself through %v",
+			name, append(visiting, name),
+		)
+	}
+	prefab, exists := pr.prefabs[name]
+	if !exists {
+		if len(visiting) > 0 {
+			return nil, fmt.Errorf(
+				"ecs: prefab %q extends unknown prefab %q",
+				visiting[len(visiting)-1], name,
+			)
+		}
+		return nil, fmt.Errorf("ecs: unknown prefab %q", name)
+	}
+
+	components := []Component{}
+	if prefab.Extends != "" {
+		inherited, err := pr.build(prefab.Extends, append(visiting, name))
+		if err != nil {
+			return nil, err
+		}
+		components = inherited
+	}
+
+	for _, constructor := range prefab.Components {
+		components = overrideComponent(components, constructor())
+	}
+	return components, nil
+}
+
+// Spawn creates an entity from the prefab
+// The overrides replace the prefab's components of the same type, or are added to them
+// (ie. a PositionComponent to place the entity)
+func (pr *PrefabRegistry) Spawn(w *World, name string, overrides ...Component) (Entity, error) {
+	components, err := pr.Components(name)
+	if err != nil {
+		return -1, err
+	}
+	for _, override := range overrides {
+		components = overrideComponent(components, override)
+	}
+
+	entity := w.EntityManager.CreateEntity()
+	addComponents(w, entity, components)
+	return entity, nil
+}
+
+// overrideComponent replaces the component of the same type in the list, or appends it
+func overrideComponent(components []Component, component Component) []Component {
+	componentType := TypeOfComponent(component)
+	for i, existing := range components {
+		if TypeOfComponent(existing) == componentType {
+			components[i] = component
+			return components
+		}
+	}
+	return append(components, component)
+}
@@ -0,0 +1,180 @@
+package ecs
+
+import "slices"
+
+// Filter narrows a query down to entities that pass an extra check
+type Filter func(w *World, entity Entity) bool
+
+// With only matches entities that also have a component of type T,
+// without fetching that component
+func With[T any]() Filter {
+	componentType := TypeOf[T]()
+	return func(w *World, entity Entity) bool {
+		return w.ComponentManager.HasComponent(entity, componentType)
+	}
+}
+
+// Without only matches entities that do not have a component of type T
+func Without[T any]() Filter {
+	componentType := TypeOf[T]()
+	return func(w *World, entity Entity) bool {
+		return !w.ComponentManager.HasComponent(entity, componentType)
+	}
+}
+
+// Added only matches entities whose component of type T was attached since the
+// running system last ran. Outside of a system every component counts as added
+func Added[T any]() Filter {
+	componentType := TypeOf[T]()
+	return func(w *World, entity Entity) bool {
+		ticks, found := w.ComponentManager.ticks(entity, componentType)
+		return found && ticks.added > w.lastRunTick
+	}
+}
+
+// Changed only matches entities whose component of type T was attached, replaced
+// or marked changed since the running system last ran
+func Changed[T any]() Filter {
+	componentType := TypeOf[T]()
+	return func(w *World, entity Entity) bool {
+		ticks, found := w.ComponentManager.ticks(entity, componentType)
+		return found && ticks.changed > w.lastRunTick
+	}
+}
+
+// Removed only matches entities that had a component of type T detached since
+// the running system last ran
+func Removed[T any]() Filter {
+	componentType := TypeOf[T]()
+	return func(w *World, entity Entity) bool {
+		return slices.Contains(
+			w.ComponentManager.removedSince(componentType, w.lastRunTick),
+			entity,
+		)
+	}
+}
+
+// RemovedEntities returns the entities that had a component of type T detached since
+// the running system last ran, including entities that have since been removed
+func RemovedEntities[T any](w *World) []Entity {
+	return w.ComponentManager.removedSince(TypeOf[T](), w.lastRunTick)
+}
+
+// Query returns all entities that have every one of the given component types
+// and pass every filter
+// Disabled entities are left out, unless Disabled is one of the component types
+func (w *World) Query(componentTypes []ComponentType, filters ...Filter) []Entity {
+	if w.ComponentManager.count(disabledType) > 0 && !slices.Contains(componentTypes, disabledType) {
+		filters = append([]Filter{enabled}, filters...)
+	}
+
+	if len(componentTypes) == 0 {
+		return w.filterEntities(w.EntityManager.GetAllEntities(), filters)
+	}
+
+	// Start from the smallest set of candidates, then check the rest against it
+	smallest := componentTypes[0]
+	for _, componentType := range componentTypes[1:] {
+		if w.ComponentManager.count(componentType) < w.ComponentManager.count(smallest) {
+			smallest = componentType
+		}
+	}
+
+	candidates := w.ComponentManager.GetAllEntitiesWithComponent(smallest)
+	matched := candidates[:0]
+	for _, entity := range candidates {
+		hasAll := true
+		for _, componentType := range componentTypes {
+			if componentType != smallest && !w.ComponentManager.HasComponent(entity, componentType) {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			matched = append(matched, entity)
+		}
+	}
+
+	return w.filterEntities(matched, filters)
+}
+
+func (w *World) filterEntities(entities []Entity, filters []Filter) []Entity {
+	if len(filters) == 0 {
+		return entities
+	}
+
+	filtered := entities[:0]
+	for _, entity := range entities {
+		passes := true
+		for _, filter := range filters {
+			if !filter(w, entity) {
+				passes = false
+				break
+			}
+		}
+		if passes {
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered
+}
+
+// Result1 is an entity matched by Query1, alongside its component
+type Result1[A any] struct {
+	Entity Entity
+	A      *A
+}
+
+// Result2 is an entity matched by Query2, alongside its components
+type Result2[A, B any] struct {
+	Entity Entity
+	A      *A
+	B      *B
+}
+
+// Result3 is an entity matched by Query3, alongside its components
+type Result3[A, B, C any] struct {
+	Entity Entity
+	A      *A
+	B      *B
+	C      *C
+}
+
+// Query1 returns every entity with a component of type A that passes the filters
+func Query1[A any](w *World, filters ...Filter) []Result1[A] {
+	entities := w.Query([]ComponentType{TypeOf[A]()}, filters...)
+
+	results := make([]Result1[A], 0, len(entities))
+	for _, entity := range entities {
+		a, _ := Get[A](w, entity)
+		results = append(results, Result1[A]{Entity: entity, A: a})
+	}
+	return results
+}
+
+// Query2 returns every entity with components of type A and B that passes the filters
+func Query2[A, B any](w *World, filters ...Filter) []Result2[A, B] {
+	entities := w.Query([]ComponentType{TypeOf[A](), TypeOf[B]()}, filters...)
+
+	results := make([]Result2[A, B], 0, len(entities))
+	for _, entity := range entities {
+		a, _ := Get[A](w, entity)
+		b, _ := Get[B](w, entity)
+		results = append(results, Result2[A, B]{Entity: entity, A: a, B: b})
+	}
+	return results
+}
+
+// Query3 returns every entity with components of type A, B and C that passes the filters
+func Query3[A, B, C any](w *World, filters ...Filter) []Result3[A, B, C] {
+	entities := w.Query([]ComponentType{TypeOf[A](), TypeOf[B](), TypeOf[C]()}, filters...)
+
+	results := make([]Result3[A, B, C], 0, len(entities))
+	for _, entity := range entities {
+		a, _ := Get[A](w, entity)
+		b, _ := Get[B](w, entity)
+		c, _ := Get[C](w, entity)
+		results = append(results, Result3[A, B, C]{Entity: entity, A: a, B: b, C: c})
+	}
+	return results
+}
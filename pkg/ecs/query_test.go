@@ -0,0 +1,186 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+// queryWorld creates a world with an entity for each combination of components the query
+// tests look for, and returns them by name
+func queryWorld() (*World, map[string]Entity) {
+	w := newTestWorld()
+	entities := map[string]Entity{}
+	spawn := func(name string, components ...Component) {
+		entity := w.EntityManager.CreateEntity()
+		addComponents(w, entity, components)
+		entities[name] = entity
+	}
+
+	spawn("empty")
+	spawn("position", &testPosition{X: 1})
+	spawn("moving", &testPosition{X: 2}, &testVelocity{DX: 2})
+	spawn("alive", &testPosition{X: 3}, &testVelocity{DX: 3}, &testHealth{HP: 3})
+	spawn("health", &testHealth{HP: 4})
+	spawn("disabled", &testPosition{X: 5}, &testVelocity{DX: 5}, &testHealth{HP: 5}, &Disabled{})
+	return w, entities
+}
+
+func TestQuery(t *testing.T) {
+	position, velocity, health := TypeOf[testPosition](), TypeOf[testVelocity](), TypeOf[testHealth]()
+
+	tests := []struct {
+		name    string
+		types   []ComponentType
+		filters []Filter
+		want    []string
+	}{
+		{
+			name: "no types matches every enabled entity",
+			want: []string{"empty", "position", "moving", "alive", "health"},
+		},
+		{
+			name:  "a single type",
+			types: []ComponentType{position},
+			want:  []string{"position", "moving", "alive"},
+		},
+		{
+			name:  "every type is included",
+			types: []ComponentType{position, velocity, health},
+			want:  []string{"alive"},
+		},
+		{
+			name:    "with",
+			types:   []ComponentType{position},
+			filters: []Filter{With[testVelocity]()},
+			want:    []string{"moving", "alive"},
+		},
+		{
+			name:    "without",
+			types:   []ComponentType{position},
+			filters: []Filter{Without[testVelocity]()},
+			want:    []string{"position"},
+		},
+		{
+			name:    "with and without",
+			types:   []ComponentType{velocity},
+			filters: []Filter{With[testPosition](), Without[testHealth]()},
+			want:    []string{"moving"},
+		},
+		{
+			name:    "without only",
+			filters: []Filter{Without[testPosition]()},
+			want:    []string{"empty", "health"},
+		},
+		{
+			name:  "asking for disabled matches disabled entities",
+			types: []ComponentType{health, TypeOf[Disabled]()},
+			want:  []string{"disabled"},
+		},
+		{
+			name:    "only asking for the type matches disabled entities",
+			types:   []ComponentType{health},
+			filters: []Filter{With[Disabled]()},
+			want:    []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w, entities := queryWorld()
+			want := []Entity{}
+			for _, name := range test.want {
+				want = append(want, entities[name])
+			}
+
+			got := w.Query(test.types, test.filters...)
+			if !slices.Equal(slices.Sorted(slices.Values(got)), want) {
+				t.Fatalf("Query = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTypedQueries(t *testing.T) {
+	w, entities := queryWorld()
+
+	// Each result's components are the entity's own
+	results1 := Query1[testPosition](w, Without[testVelocity]())
+	if len(results1) != 1 || results1[0].Entity != entities["position"] || results1[0].A.X != 1 {
+		t.Fatalf("Query1 = %+v, want the position entity with X 1", results1)
+	}
+
+	results2 := Query2[testPosition, testVelocity](w)
+	if len(results2) != 2 {
+		t.Fatalf("Query2 = %+v, want 2 results", results2)
+	}
+	for _, result := range results2 {
+		position, _ := Get[testPosition](w, result.Entity)
+		if result.A != position || result.A.X != result.B.DX {
+			t.Fatalf("Query2 result %+v doesn't hold its entity's components", result)
+		}
+	}
+
+	results3 := Query3[testPosition, testVelocity, testHealth](w)
+	if len(results3) != 1 || results3[0].Entity != entities["alive"] {
+		t.Fatalf("Query3 = %+v, want the alive entity", results3)
+	}
+	if result := results3[0]; result.A.X != 3 || result.B.DX != 3 || result.C.HP != 3 {
+		t.Fatalf("Query3 components = %+v %+v %+v, want 3s", result.A, result.B, result.C)
+	}
+
+	// The components are pointers into storage, so changing them changes the entity
+	results3[0].C.HP = 30
+	if health, _ := Get[testHealth](w, entities["alive"]); health.HP != 30 {
+		t.Fatalf("HP after changing the result = %d, want 30", health.HP)
+	}
+
+	// Enabling an entity brings it back
+	Enable(w, entities["disabled"])
+	if results := Query3[testPosition, testVelocity, testHealth](w); len(results) != 2 {
+		t.Fatalf("Query3 after enabling = %+v, want 2 results", results)
+	}
+}
+
+func TestChangeFilters(t *testing.T) {
+	w := newTestWorld()
+	added := w.EntityManager.CreateEntity()
+	changed := w.EntityManager.CreateEntity()
+	removed := w.EntityManager.CreateEntity()
+	for _, entity := range []Entity{changed, removed} {
+		Add(w, entity, &testPosition{})
+	}
+
+	var gotAdded, gotChanged, gotRemoved []Entity
+	w.AddSystem(systemFunc(func(w *World) {
+		gotAdded = w.Query([]ComponentType{TypeOf[testPosition]()}, Added[testPosition]())
+		gotChanged = w.Query([]ComponentType{TypeOf[testPosition]()}, Changed[testPosition]())
+		gotRemoved = RemovedEntities[testPosition](w)
+	}), Named("observer"))
+
+	// Everything is new to the system the first time it runs
+	w.Update()
+	if !slices.Equal(gotAdded, []Entity{changed, removed}) {
+		t.Fatalf("first update: added = %v, want %v", gotAdded, []Entity{changed, removed})
+	}
+
+	Add(w, added, &testPosition{})
+	MarkChanged[testPosition](w, changed)
+	Remove[testPosition](w, removed)
+	w.Update()
+
+	if !slices.Equal(gotAdded, []Entity{added}) {
+		t.Fatalf("added = %v, want %v", gotAdded, []Entity{added})
+	}
+	if !slices.Equal(gotChanged, []Entity{changed, added}) {
+		t.Fatalf("changed = %v, want %v", gotChanged, []Entity{changed, added})
+	}
+	if !slices.Equal(gotRemoved, []Entity{removed}) {
+		t.Fatalf("removed = %v, want %v", gotRemoved, []Entity{removed})
+	}
+
+	// Removals the system has seen are pruned
+	w.Update()
+	if len(gotRemoved) != 0 {
+		t.Fatalf("removed after being seen = %v, want none", gotRemoved)
+	}
+}
@@ -0,0 +1,224 @@
+package ecs
+
+import "slices"
+
+// Relation is a component that points from the entity it is attached to (the source)
+// at another entity (the target), ie. an item that is ChildOf the creature holding it
+// Relations registered with RegisterRelation are indexed by target, and follow a
+// DespawnPolicy when their target is removed
+type Relation interface {
+	Component
+	RelationTarget() Entity
+	SetRelationTarget(target Entity)
+}
+
+// relationPtr constrains PR to be a pointer to R that implements Relation
+type relationPtr[R any] interface {
+	*R
+	Relation
+}
+
+// DespawnPolicy decides what happens to the sources of a relation when its target is removed
+type DespawnPolicy int
+
+const (
+	// Orphan detaches the relation from the sources, leaving them in the world
+	Orphan DespawnPolicy = iota
+	// DespawnRecursive removes the sources as well, along with their own sources
+	DespawnRecursive
+	// Reparent points the sources at the removed target's own target,
+	// or orphans them if it has none
+	Reparent
+)
+
+// ChildOf is the built-in parent / child relation, registered with DespawnRecursive
+type ChildOf struct {
+	Parent Entity
+}
+
+func (c ChildOf) IsComponent() {}
+
+func (c *ChildOf) RelationTarget() Entity { return c.Parent }
+
+func (c *ChildOf) SetRelationTarget(target Entity) { c.Parent = target }
+
+// relationIndex tracks the sources pointing at each target for one relation type
+type relationIndex struct {
+	componentType ComponentType
+	policy        DespawnPolicy
+	sources       map[Entity][]Entity
+}
+
+// RegisterRelation indexes the relation R by target and sets the policy used when a
+// target is removed. Registering a relation again only changes its policy
+func RegisterRelation[R any, PR relationPtr[R]](w *World, policy DespawnPolicy) {
+	componentType := TypeOf[R]()
+	if index, exists := w.relations[componentType]; exists {
+		index.policy = policy
+		return
+	}
+
+	index := &relationIndex{
+		componentType: componentType,
+		policy:        policy,
+		sources:       make(map[Entity][]Entity),
+	}
+	w.relations[componentType] = index
+	w.relationTypes = append(w.relationTypes, componentType)
+
+	OnAdd(w, func(source Entity, relation *R) {
+		index.link(source, PR(relation).RelationTarget())
+	})
+	OnReplace(w, func(source Entity, old, new *R) {
+		index.unlink(source, PR(old).RelationTarget())
+		index.link(source, PR(new).RelationTarget())
+	})
+	OnRemove(w, func(source Entity, relation *R) {
+		index.unlink(source, PR(relation).RelationTarget())
+	})
+}
+
+func (ri *relationIndex) link(source, target Entity) {
+	ri.sources[target] = append(ri.sources[target], source)
+}
+
+func (ri *relationIndex) unlink(source, target Entity) {
+	sources := slices.DeleteFunc(ri.sources[target], func(e Entity) bool {
+		return e == source
+	})
+	if len(sources) == 0 {
+		delete(ri.sources, target)
+	} else {
+		ri.sources[target] = sources
+	}
+}
+
+// Sources returns the entities whose relation R points at the target, in the order
+// the relations were added
+func Sources[R any, PR relationPtr[R]](w *World, target Entity) []Entity {
+	if index, exists := w.relations[TypeOf[R]()]; exists {
+		return slices.Clone(index.sources[target])
+	}
+
+	// Unregistered relations aren't indexed, so fall back to a scan
+	sources := []Entity{}
+	for _, source := range w.ComponentManager.GetAllEntitiesWithComponent(TypeOf[R]()) {
+		relation, _ := Get[R](w, source)
+		if PR(relation).RelationTarget() == target {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// Target returns the entity that the source's relation R points at
+func Target[R any, PR relationPtr[R]](w *World, source Entity) (Entity, bool) {
+	relation, found := Get[R](w, source)
+	if !found {
+		return -1, false
+	}
+	return PR(relation).RelationTarget(), true
+}
+
+// RelatedTo only matches entities whose relation R points at the target
+func RelatedTo[R any](target Entity) Filter {
+	componentType := TypeOf[R]()
+	return func(w *World, entity Entity) bool {
+		component, found := w.ComponentManager.GetComponent(entity, componentType)
+		return found && component.(Relation).RelationTarget() == target
+	}
+}
+
+// Children returns the entities that are ChildOf the parent
+func Children(w *World, parent Entity) []Entity {
+	return Sources[ChildOf](w, parent)
+}
+
+// Parent returns the entity that the child is ChildOf
+func Parent(w *World, child Entity) (Entity, bool) {
+	return Target[ChildOf](w, child)
+}
+
+// SetParent makes the child ChildOf the parent, replacing any previous parent
+func SetParent(w *World, child, parent Entity) {
+	Add(w, child, &ChildOf{Parent: parent})
+}
+
+// RemoveParent detaches the child from its parent, leaving it in the world
+func RemoveParent(w *World, child Entity) {
+	Remove[ChildOf](w, child)
+}
+
+// Descendants returns the children of the entity, their children and so on,
+// each child directly after its parent
+// Every entity is listed once, so relations that loop back round (ie. an entity that is
+// ChildOf its own child) don't go on forever
+func Descendants(w *World, entity Entity) []Entity {
+	descendants := []Entity{}
+	visited := map[Entity]bool{entity: true}
+
+	var visit func(parent Entity)
+	visit = func(parent Entity) {
+		for _, child := range Children(w, parent) {
+			if visited[child] {
+				continue
+			}
+			visited[child] = true
+			descendants = append(descendants, child)
+			visit(child)
+		}
+	}
+	visit(entity)
+	return descendants
+}
+
+// despawnRelated applies each relation's DespawnPolicy to the sources pointing at a
+// target that is being removed. The target's own relations are passed in since it
+// has already lost its components
+func (w *World) despawnRelated(target Entity, targetsOfTarget map[ComponentType]Entity) {
+	for _, componentType := range w.relationTypes {
+		index := w.relations[componentType]
+		sources := slices.Clone(index.sources[target])
+		for _, source := range sources {
+			switch index.policy {
+			case DespawnRecursive:
+				w.RemoveEntity(source)
+			case Reparent:
+				newTarget, hasTarget := targetsOfTarget[componentType]
+				if hasTarget && w.IsAlive(newTarget) && newTarget != source {
+					w.retarget(source, componentType, newTarget)
+				} else {
+					w.ComponentManager.RemoveComponent(source, componentType)
+				}
+			default:
+				w.ComponentManager.RemoveComponent(source, componentType)
+			}
+		}
+		delete(index.sources, target)
+	}
+}
+
+// retarget points the source's relation at a new target in place
+func (w *World) retarget(source Entity, componentType ComponentType, target Entity) {
+	component, found := w.ComponentManager.GetComponent(source, componentType)
+	if !found {
+		return
+	}
+	relation := component.(Relation)
+	index := w.relations[componentType]
+	index.unlink(source, relation.RelationTarget())
+	relation.SetRelationTarget(target)
+	index.link(source, target)
+	w.ComponentManager.MarkChanged(source, componentType)
+}
+
+// relationTargets returns the targets of every registered relation on the entity
+func (w *World) relationTargets(entity Entity) map[ComponentType]Entity {
+	targets := map[ComponentType]Entity{}
+	for _, componentType := range w.relationTypes {
+		if component, found := w.ComponentManager.GetComponent(entity, componentType); found {
+			targets[componentType] = component.(Relation).RelationTarget()
+		}
+	}
+	return targets
+}
@@ -0,0 +1,45 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDescendantsListsEachEntityOnce(t *testing.T) {
+	w := newTestWorld()
+	root := w.EntityManager.CreateEntity()
+	child := w.EntityManager.CreateEntity()
+	grandchild := w.EntityManager.CreateEntity()
+	SetParent(w, child, root)
+	SetParent(w, grandchild, child)
+
+	want := []Entity{child, grandchild}
+	if got := Descendants(w, root); !slices.Equal(got, want) {
+		t.Fatalf("descendants = %v, want %v", got, want)
+	}
+
+	// Loop the hierarchy back round to the root
+	SetParent(w, root, grandchild)
+	if got := Descendants(w, root); !slices.Equal(got, want) {
+		t.Fatalf("descendants of a cycle = %v, want %v", got, want)
+	}
+}
+
+func TestRemovingParentDespawnsChildren(t *testing.T) {
+	w := newTestWorld()
+	parent := w.EntityManager.CreateEntity()
+	child := w.EntityManager.CreateEntity()
+	grandchild := w.EntityManager.CreateEntity()
+	SetParent(w, child, parent)
+	SetParent(w, grandchild, child)
+
+	w.RemoveEntity(parent)
+	for _, entity := range []Entity{parent, child, grandchild} {
+		if w.IsAlive(entity) {
+			t.Fatalf("entity %d is still alive after its ancestor was removed", entity)
+		}
+	}
+	if children := Children(w, parent); len(children) != 0 {
+		t.Fatalf("removed parent still has children %v", children)
+	}
+}
@@ -0,0 +1,35 @@
+package ecs
+
+import "reflect"
+
+// Resources are world-level singletons, keyed by their type, for data that
+// doesn't belong to any one entity (ie. map size or the random number generator)
+// Systems can declare access to them with Reads and Writes using TypeOf
+// Systems running in a parallel batch only see resources they set or remove once the
+// batch is over, since the batch shares the world's resources
+
+// SetResource stores the resource of type T, replacing any previous one
+func SetResource[T any](w *World, resource *T) {
+	resourceType := reflect.TypeFor[T]()
+	registerType(resourceType)
+	w.deferInParallel(func(w *World) {
+		w.resources[resourceType] = resource
+	})
+}
+
+// Resource returns the resource of type T
+func Resource[T any](w *World) (*T, bool) {
+	resource, found := w.resources[reflect.TypeFor[T]()]
+	if !found {
+		return nil, false
+	}
+	return resource.(*T), true
+}
+
+// RemoveResource removes the resource of type T
+func RemoveResource[T any](w *World) {
+	resourceType := reflect.TypeFor[T]()
+	w.deferInParallel(func(w *World) {
+		delete(w.resources, resourceType)
+	})
+}
@@ -0,0 +1,270 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Stage groups systems that run together during World.Update
+// Stages always run in the order they are declared here
+type Stage int
+
+const (
+	PreUpdate Stage = iota
+	Intent
+	Resolve
+	PostUpdate
+	Cleanup
+)
+
+var stageNames = map[Stage]string{
+	PreUpdate:  "PreUpdate",
+	Intent:     "Intent",
+	Resolve:    "Resolve",
+	PostUpdate: "PostUpdate",
+	Cleanup:    "Cleanup",
+}
+
+var stages = []Stage{PreUpdate, Intent, Resolve, PostUpdate, Cleanup}
+
+func (s Stage) String() string {
+	if name, ok := stageNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("Stage(%d)", int(s))
+}
+
+// SystemOption configures how a system is scheduled when it is added to the world
+type SystemOption func(*scheduledSystem)
+
+// Named sets the name other systems use to refer to this one in Before and After
+// Systems are named after their Go type by default (ie. "*systems.MovementSystem")
+func Named(name string) SystemOption {
+	return func(s *scheduledSystem) {
+		s.name = name
+	}
+}
+
+// InStage sets the stage the system runs in, systems run in the Resolve stage by default
+func InStage(stage Stage) SystemOption {
+	return func(s *scheduledSystem) {
+		s.stage = stage
+	}
+}
+
+// Before makes the system run before the named systems
+func Before(names ...string) SystemOption {
+	return func(s *scheduledSystem) {
+		s.before = append(s.before, names...)
+	}
+}
+
+// After makes the system run after the named systems
+func After(names ...string) SystemOption {
+	return func(s *scheduledSystem) {
+		s.after = append(s.after, names...)
+	}
+}
+
+// Reads declares component (or resource) types the system reads
+// Systems that declare their access can run in parallel with other systems in
+// the same stage, as long as neither writes a type the other reads or writes
+func Reads(componentTypes ...ComponentType) SystemOption {
+	return func(s *scheduledSystem) {
+		s.access.declared = true
+		s.access.reads = append(s.access.reads, componentTypes...)
+	}
+}
+
+// Writes declares component (or resource) types the system modifies, adds or removes
+// (directly or through Commands). A system that spawns or despawns entities
+// should declare every component type those entities can have
+// Systems in a parallel batch can add and remove the component types they write directly,
+// but should spawn and despawn entities through Commands
+func Writes(componentTypes ...ComponentType) SystemOption {
+	return func(s *scheduledSystem) {
+		s.access.declared = true
+		s.access.writes = append(s.access.writes, componentTypes...)
+	}
+}
+
+type scheduledSystem struct {
+	system System
+	name   string
+	stage  Stage
+	before []string
+	after  []string
+	access systemAccess
+	order  int // Position the system was added in, used to break ties
+
+	lastRun uint64 // Change tick the system last ran at
+}
+
+// systemAccess is the set of component types a system declared it uses
+// Systems that declare nothing are exclusive, and never run alongside another system
+type systemAccess struct {
+	declared bool
+	reads    []ComponentType
+	writes   []ComponentType
+}
+
+func (a systemAccess) conflicts(other systemAccess) bool {
+	if !a.declared || !other.declared {
+		return true
+	}
+	for _, written := range a.writes {
+		if slices.Contains(other.reads, written) || slices.Contains(other.writes, written) {
+			return true
+		}
+	}
+	for _, written := range other.writes {
+		if slices.Contains(a.reads, written) {
+			return true
+		}
+	}
+	return false
+}
+
+// dependsOn reports whether there is a direct Before / After constraint between the systems
+func (s *scheduledSystem) dependsOn(other *scheduledSystem) bool {
+	return slices.Contains(s.after, other.name) ||
+		slices.Contains(s.before, other.name) ||
+		slices.Contains(other.after, s.name) ||
+		slices.Contains(other.before, s.name)
+}
+
+// batchSchedule splits the ordered systems into batches that can run in parallel
+// Each batch holds consecutive systems of one stage with no conflicting access and no
+// ordering constraint between them, so running a batch concurrently gives the same
+// result as running it in order
+func batchSchedule(ordered []*scheduledSystem) [][]*scheduledSystem {
+	var batches [][]*scheduledSystem
+	var batch []*scheduledSystem
+	for _, s := range ordered {
+		fits := len(batch) > 0 && batch[0].stage == s.stage
+		for _, member := range batch {
+			if !fits {
+				break
+			}
+			fits = !member.access.conflicts(s.access) && !member.dependsOn(s)
+		}
+
+		if !fits && len(batch) > 0 {
+			batches = append(batches, batch)
+			batch = nil
+		}
+		batch = append(batch, s)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// buildSchedule orders the systems by stage, then by their Before / After constraints
+// Systems without a constraint between them keep the order they were added in
+func buildSchedule(systems []*scheduledSystem) ([]*scheduledSystem, error) {
+	byName := make(map[string]*scheduledSystem, len(systems))
+	for _, s := range systems {
+		if _, exists := byName[s.name]; exists {
+			return nil, fmt.Errorf("ecs: duplicate system name %q", s.name)
+		}
+		if _, known := stageNames[s.stage]; !known {
+			return nil, fmt.Errorf("ecs: system %q is in unknown stage %s", s.name, s.stage)
+		}
+		byName[s.name] = s
+	}
+
+	// Collect "runs before" edges between systems in the same stage
+	successors := make(map[*scheduledSystem][]*scheduledSystem)
+	inDegree := make(map[*scheduledSystem]int)
+	addEdge := func(first, then *scheduledSystem) error {
+		switch {
+		case first.stage > then.stage:
+			return fmt.Errorf(
+				"ecs: %q must run before %q, but its stage %s runs after %s",
+				first.name, then.name, first.stage, then.stage,
+			)
+		case first.stage == then.stage:
+			successors[first] = append(successors[first], then)
+			inDegree[then]++
+		}
+		return nil
+	}
+
+	for _, s := range systems {
+		for _, name := range s.before {
+			other, exists := byName[name]
+			if !exists {
+				return nil, fmt.Errorf("ecs: system %q runs before unknown system %q", s.name, name)
+			}
+			if err := addEdge(s, other); err != nil {
+				return nil, err
+			}
+		}
+		for _, name := range s.after {
+			other, exists := byName[name]
+			if !exists {
+				return nil, fmt.Errorf("ecs: system %q runs after unknown system %q", s.name, name)
+			}
+			if err := addEdge(other, s); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Topologically sort each stage, always picking the earliest added ready system
+	ordered := make([]*scheduledSystem, 0, len(systems))
+	for _, stage := range stages {
+		var ready, pending []*scheduledSystem
+		for _, s := range systems {
+			if s.stage != stage {
+				continue
+			}
+			pending = append(pending, s)
+			if inDegree[s] == 0 {
+				ready = append(ready, s)
+			}
+		}
+
+		sorted := 0
+		for len(ready) > 0 {
+			next := ready[0]
+			ready = ready[1:]
+			ordered = append(ordered, next)
+			sorted++
+
+			for _, successor := range successors[next] {
+				inDegree[successor]--
+				if inDegree[successor] == 0 {
+					ready = append(ready, successor)
+					slices.SortFunc(ready, func(a, b *scheduledSystem) int {
+						return a.order - b.order
+					})
+				}
+			}
+		}
+
+		if sorted < len(pending) {
+			var cycle []string
+			for _, s := range pending {
+				if inDegree[s] > 0 {
+					cycle = append(cycle, s.name)
+				}
+			}
+			return nil, fmt.Errorf(
+				"ecs: ordering cycle in stage %s between systems %s",
+				stage,
+				strings.Join(cycle, ", "),
+			)
+		}
+	}
+
+	return ordered, nil
+}
+
+func systemName(system System) string {
+	return reflect.TypeOf(system).String()
+}
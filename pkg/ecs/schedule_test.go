@@ -0,0 +1,288 @@
+package ecs
+
+import (
+	"io"
+	"log"
+	"slices"
+	"testing"
+	"time"
+)
+
+type testPosition struct {
+	X, Y int
+}
+
+func (testPosition) IsComponent() {}
+
+type testVelocity struct {
+	DX, DY int
+}
+
+func (testVelocity) IsComponent() {}
+
+type testHealth struct {
+	HP int
+}
+
+func (testHealth) IsComponent() {}
+
+type testCounter struct {
+	Count int
+}
+
+// systemFunc adapts a function to the System interface
+type systemFunc func(w *World)
+
+func (f systemFunc) Update(w *World) { f(w) }
+
+func newTestWorld() *World {
+	return NewWorld(log.New(io.Discard, "", 0))
+}
+
+// batchNames returns the names of the systems in each batch of the world's schedule
+func batchNames(t *testing.T, w *World) [][]string {
+	t.Helper()
+	if err := w.BuildSchedule(); err != nil {
+		t.Fatalf("building schedule: %v", err)
+	}
+	names := [][]string{}
+	for _, batch := range w.schedule {
+		batchNames := []string{}
+		for _, scheduled := range batch {
+			batchNames = append(batchNames, scheduled.name)
+		}
+		names = append(names, batchNames)
+	}
+	return names
+}
+
+func TestBatchSchedule(t *testing.T) {
+	noop := systemFunc(func(*World) {})
+	position, velocity := TypeOf[testPosition](), TypeOf[testVelocity]()
+
+	tests := []struct {
+		name    string
+		add     func(w *World)
+		batches [][]string
+	}{
+		{
+			name: "disjoint writes share a batch",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Writes(position))
+				w.AddSystem(noop, Named("b"), Writes(velocity))
+			},
+			batches: [][]string{{"a", "b"}},
+		},
+		{
+			name: "shared reads share a batch",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Reads(position), Writes(velocity))
+				w.AddSystem(noop, Named("b"), Reads(position))
+			},
+			batches: [][]string{{"a", "b"}},
+		},
+		{
+			name: "write then read conflicts",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Writes(position))
+				w.AddSystem(noop, Named("b"), Reads(position))
+			},
+			batches: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "read then write conflicts",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Reads(position))
+				w.AddSystem(noop, Named("b"), Writes(position))
+			},
+			batches: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "undeclared systems run alone",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Writes(position))
+				w.AddSystem(noop, Named("b"))
+				w.AddSystem(noop, Named("c"), Writes(velocity))
+			},
+			batches: [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name: "ordering constraints split batches",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Writes(position))
+				w.AddSystem(noop, Named("b"), Writes(velocity), After("a"))
+			},
+			batches: [][]string{{"a"}, {"b"}},
+		},
+		{
+			name: "stages split batches",
+			add: func(w *World) {
+				w.AddSystem(noop, Named("a"), Writes(position), InStage(Intent))
+				w.AddSystem(noop, Named("b"), Writes(velocity), InStage(Resolve))
+			},
+			batches: [][]string{{"a"}, {"b"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := newTestWorld()
+			test.add(w)
+			if got := batchNames(t, w); !slices.EqualFunc(got, test.batches, slices.Equal) {
+				t.Fatalf("batches = %v, want %v", got, test.batches)
+			}
+		})
+	}
+}
+
+func TestBuildScheduleRejectsCycles(t *testing.T) {
+	noop := systemFunc(func(*World) {})
+	w := newTestWorld()
+	w.AddSystem(noop, Named("a"), After("b"))
+	w.AddSystem(noop, Named("b"), After("a"))
+	if err := w.BuildSchedule(); err == nil {
+		t.Fatal("expected an ordering cycle error")
+	}
+}
+
+// spawnOrder returns the position X of every entity, in entity order
+func spawnOrder(w *World) []int {
+	order := []int{}
+	for _, positioned := range Query1[testPosition](w) {
+		order = append(order, positioned.A.X)
+	}
+	return order
+}
+
+func TestParallelBatchMergesInScheduleOrder(t *testing.T) {
+	const systems = 4
+	w := newTestWorld()
+	events := []int{}
+	Subscribe(w, func(event int) {
+		events = append(events, event)
+	})
+
+	for i := range systems {
+		// Systems added later finish first, so merging in completion order would reverse them
+		// The spawns only happen at the sync point, so the systems only read
+		delay := time.Duration(systems-i) * time.Millisecond
+		w.AddSystem(systemFunc(func(w *World) {
+			time.Sleep(delay)
+			w.Commands().Spawn(&testPosition{X: i})
+			Emit(w, i)
+		}), Named(string(rune('a'+i))), Reads(TypeOf[testVelocity]()))
+	}
+	if batches := batchNames(t, w); len(batches) != 1 || len(batches[0]) != systems {
+		t.Fatalf("batches = %v, want a single batch of %d systems", batches, systems)
+	}
+
+	want := []int{0, 1, 2, 3}
+	for update := range 5 {
+		events = events[:0]
+		w.Update()
+
+		if !slices.Equal(events, want) {
+			t.Fatalf("update %d: events = %v, want %v", update, events, want)
+		}
+		if got := spawnOrder(w)[update*systems:]; !slices.Equal(got, want) {
+			t.Fatalf("update %d: spawned = %v, want %v", update, got, want)
+		}
+	}
+}
+
+func TestParallelSystemsAddUnregisteredTypes(t *testing.T) {
+	w := newTestWorld()
+	entities := []Entity{}
+	for range 100 {
+		entities = append(entities, w.EntityManager.CreateEntity())
+	}
+
+	// Neither type has been stored yet, so adding them registers their storage
+	w.AddSystem(systemFunc(func(w *World) {
+		for _, entity := range entities {
+			Add(w, entity, &testHealth{HP: 10})
+		}
+	}), Named("health"), Writes(TypeOf[testHealth]()))
+	w.AddSystem(systemFunc(func(w *World) {
+		for _, entity := range entities {
+			Add(w, entity, &testVelocity{DX: 1})
+		}
+	}), Named("velocity"), Writes(TypeOf[testVelocity]()))
+	if batches := batchNames(t, w); len(batches) != 1 {
+		t.Fatalf("batches = %v, want a single batch", batches)
+	}
+
+	w.Update()
+	if got := len(Query2[testHealth, testVelocity](w)); got != len(entities) {
+		t.Fatalf("entities with both components = %d, want %d", got, len(entities))
+	}
+}
+
+func TestParallelSystemsRemoveDifferentTypes(t *testing.T) {
+	w := newTestWorld()
+	entities := []Entity{}
+	for i := range 100 {
+		entity := w.EntityManager.CreateEntity()
+		Add(w, entity, &testPosition{X: i})
+		Add(w, entity, &testVelocity{DX: i})
+		entities = append(entities, entity)
+	}
+
+	// Each system removes its own type directly, so they share a batch but each removal
+	// is logged by a different storage
+	w.AddSystem(systemFunc(func(w *World) {
+		for _, entity := range entities {
+			Remove[testPosition](w, entity)
+		}
+	}), Named("positions"), Writes(TypeOf[testPosition]()))
+	w.AddSystem(systemFunc(func(w *World) {
+		for _, entity := range entities {
+			Remove[testVelocity](w, entity)
+		}
+	}), Named("velocities"), Writes(TypeOf[testVelocity]()))
+
+	var removedPositions, removedVelocities []Entity
+	w.AddSystem(systemFunc(func(w *World) {
+		removedPositions = RemovedEntities[testPosition](w)
+		removedVelocities = RemovedEntities[testVelocity](w)
+	}), Named("observer"), After("positions", "velocities"))
+
+	if batches := batchNames(t, w); len(batches) != 2 || len(batches[0]) != 2 {
+		t.Fatalf("batches = %v, want the removing systems in one batch", batches)
+	}
+	w.Update()
+
+	if !slices.Equal(removedPositions, entities) {
+		t.Fatalf("removed positions = %v, want %v", removedPositions, entities)
+	}
+	if !slices.Equal(removedVelocities, entities) {
+		t.Fatalf("removed velocities = %v, want %v", removedVelocities, entities)
+	}
+}
+
+func TestParallelSetResourceIsDeferred(t *testing.T) {
+	w := newTestWorld()
+
+	setDuringBatch := make([]bool, 2)
+	w.AddSystem(systemFunc(func(w *World) {
+		SetResource(w, &testCounter{Count: 1})
+		_, setDuringBatch[0] = Resource[testCounter](w)
+	}), Named("counter"), Writes(TypeOf[testCounter]()))
+	w.AddSystem(systemFunc(func(w *World) {
+		SetResource(w, &testHealth{HP: 2})
+		_, setDuringBatch[1] = Resource[testHealth](w)
+	}), Named("health"), Writes(TypeOf[testHealth]()))
+	if batches := batchNames(t, w); len(batches) != 1 {
+		t.Fatalf("batches = %v, want a single batch", batches)
+	}
+	w.Update()
+
+	if setDuringBatch[0] || setDuringBatch[1] {
+		t.Fatal("resources were set while the batch was running")
+	}
+	counter, hasCounter := Resource[testCounter](w)
+	health, hasHealth := Resource[testHealth](w)
+	if !hasCounter || counter.Count != 1 || !hasHealth || health.HP != 2 {
+		t.Fatalf("resources after the batch = %v %v, want both set", counter, health)
+	}
+}
@@ -0,0 +1,152 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// JSONSnapshotVersion is the version written to JSON snapshots, and the only one LoadJSON reads
+const JSONSnapshotVersion = 1
+
+// jsonSnapshot is the document written by SaveJSON
+// Entity IDs are kept as they are, so entity references inside components
+// (ie. InventoryComponent.Items) still point at the same entities once loaded
+type jsonSnapshot struct {
+	Version        int             `json:"version"`
+	Generations    []int           `json:"generations"`
+	FreeIndices    []int           `json:"freeIndices"`
+	ComponentTypes []ComponentType `json:"componentTypes"`
+	Entities       []jsonEntity    `json:"entities"`
+}
+
+type jsonEntity struct {
+	ID         Entity                            `json:"id"`
+	Components map[ComponentType]json.RawMessage `json:"components"`
+}
+
+// SaveJSON writes every entity and its components to a versioned JSON document
+// Component types must be registered with TypeOf to be saved
+func (w *World) SaveJSON(writer io.Writer) error {
+	snapshot := jsonSnapshot{
+		Version:        JSONSnapshotVersion,
+		Generations:    w.EntityManager.generations,
+		FreeIndices:    w.EntityManager.freeIndices,
+		ComponentTypes: w.ComponentManager.componentTypes,
+		Entities:       []jsonEntity{},
+	}
+
+	for _, entity := range w.EntityManager.GetAllEntities() {
+		saved := jsonEntity{ID: entity, Components: map[ComponentType]json.RawMessage{}}
+		for _, componentType := range w.ComponentManager.componentTypes {
+			component, found := w.ComponentManager.GetComponent(entity, componentType)
+			if !found {
+				continue
+			}
+			if _, registered := lookupComponentType(componentType); !registered {
+				return fmt.Errorf("ecs: component type %q is not registered", componentType)
+			}
+
+			data, err := json.Marshal(component)
+			if err != nil {
+				return fmt.Errorf("ecs: encoding %s of entity %d: %w", componentType, entity, err)
+			}
+			saved.Components[componentType] = data
+		}
+		snapshot.Entities = append(snapshot.Entities, saved)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// LoadJSON rebuilds the entities and components written by SaveJSON into an empty world
+// Components are attached through the ComponentManager, so hooks fire as they load
+func (w *World) LoadJSON(reader io.Reader) error {
+	var snapshot jsonSnapshot
+	if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return fmt.Errorf("ecs: decoding snapshot: %w", err)
+	}
+	if snapshot.Version != JSONSnapshotVersion {
+		return fmt.Errorf("ecs: unsupported snapshot version %d", snapshot.Version)
+	}
+
+	entities := make([]Entity, len(snapshot.Entities))
+	for i, saved := range snapshot.Entities {
+		entities[i] = saved.ID
+	}
+	if err := w.restoreEntities(snapshot.Generations, snapshot.FreeIndices, entities); err != nil {
+		return err
+	}
+
+	// Register types in their saved order, so removal hooks run in the same order as before
+	for _, componentType := range snapshot.ComponentTypes {
+		w.ComponentManager.RegisterComponentType(componentType)
+	}
+	for _, saved := range snapshot.Entities {
+		for _, componentType := range slices.Sorted(maps.Keys(saved.Components)) {
+			w.ComponentManager.RegisterComponentType(componentType)
+		}
+	}
+
+	for _, saved := range snapshot.Entities {
+		for _, componentType := range w.ComponentManager.componentTypes {
+			data, found := saved.Components[componentType]
+			if !found {
+				continue
+			}
+			component, err := newComponent(componentType)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(data, component); err != nil {
+				return fmt.Errorf("ecs: decoding %s of entity %d: %w", componentType, saved.ID, err)
+			}
+			w.ComponentManager.AddComponent(saved.ID, componentType, component)
+		}
+	}
+
+	return nil
+}
+
+// restoreEntities replaces the entity manager's state with a saved one
+// It refuses to load into a world that already has entities
+func (w *World) restoreEntities(generations, freeIndices []int, entities []Entity) error {
+	if len(w.EntityManager.GetAllEntities()) > 0 {
+		return fmt.Errorf("ecs: snapshots can only be loaded into an empty world")
+	}
+	if len(generations) == 0 {
+		return fmt.Errorf("ecs: snapshot has no entity generations")
+	}
+
+	alive := make([]bool, len(generations))
+	for _, entity := range entities {
+		index := entity.Index()
+		if entity <= 0 || index >= len(generations) || generations[index] != entity.Generation() {
+			return fmt.Errorf("ecs: snapshot entity %d doesn't match its generation", entity)
+		}
+		alive[index] = true
+	}
+
+	w.EntityManager.generations = slices.Clone(generations)
+	w.EntityManager.alive = alive
+	w.EntityManager.freeIndices = append([]int{}, freeIndices...)
+	return nil
+}
+
+// newComponent allocates a zero component of the type registered for the key
+func newComponent(componentType ComponentType) (Component, error) {
+	t, registered := lookupComponentType(componentType)
+	if !registered {
+		return nil, fmt.Errorf("ecs: unknown component type %q", componentType)
+	}
+	component, ok := reflect.New(t).Interface().(Component)
+	if !ok {
+		return nil, fmt.Errorf("ecs: %q is not a component type", componentType)
+	}
+	return component, nil
+}
@@ -0,0 +1,231 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// BinarySnapshotVersion is the format version written in the binary snapshot header
+const BinarySnapshotVersion = 1
+
+// binarySnapshotMagic starts every binary snapshot, ahead of the format version
+var binarySnapshotMagic = [4]byte{'E', 'C', 'S', 'W'}
+
+// binarySnapshot is the body of a binary snapshot, encoded with gob after the header
+// Components are stored in a column per type, so each type's layout is only written once
+// Columns are kept in registration order, even when empty, so hooks run in the same order
+type binarySnapshot struct {
+	Generations []int
+	FreeIndices []int
+	Entities    []Entity
+	Components  []binaryComponents
+	Resources   binaryValues
+	Events      binaryValues
+}
+
+// binaryComponents holds every component of one type, along with the schema
+// version it was saved at
+type binaryComponents struct {
+	Type     ComponentType
+	Version  int
+	Entities []Entity
+	Data     []byte // Gob stream of the components, in the same order as Entities
+}
+
+// binaryValues holds a list of values of mixed types, ie. the resources or event queue
+type binaryValues struct {
+	Types []string
+	Data  []byte // Gob stream of the values, in the same order as Types
+}
+
+// SaveBinary writes the entities, components, resources and queued events of the
+// world in a compact binary format, headed by the format version
+// Every component, resource and event type must be encodable with encoding/gob
+func (w *World) SaveBinary(writer io.Writer) error {
+	entities := w.EntityManager.GetAllEntities()
+	snapshot := binarySnapshot{
+		Generations: w.EntityManager.generations,
+		FreeIndices: w.EntityManager.freeIndices,
+		Entities:    entities,
+	}
+
+	for _, componentType := range w.ComponentManager.componentTypes {
+		if _, registered := lookupComponentType(componentType); !registered {
+			return fmt.Errorf("ecs: component type %q is not registered", componentType)
+		}
+
+		saved := binaryComponents{Type: componentType, Version: SchemaVersion(componentType)}
+		var data bytes.Buffer
+		encoder := gob.NewEncoder(&data)
+		for _, entity := range entities {
+			component, found := w.ComponentManager.GetComponent(entity, componentType)
+			if !found {
+				continue
+			}
+			if err := encoder.Encode(component); err != nil {
+				return fmt.Errorf("ecs: encoding %s of entity %d: %w", componentType, entity, err)
+			}
+			saved.Entities = append(saved.Entities, entity)
+		}
+		saved.Data = data.Bytes()
+		snapshot.Components = append(snapshot.Components, saved)
+	}
+
+	// Resources are saved by name, so the file doesn't depend on map order
+	resourceTypes := map[string]reflect.Type{}
+	for resourceType := range w.resources {
+		resourceTypes[typeName(resourceType)] = resourceType
+	}
+	resources := []any{}
+	for _, name := range slices.Sorted(maps.Keys(resourceTypes)) {
+		resources = append(resources, w.resources[resourceTypes[name]])
+	}
+
+	var err error
+	if snapshot.Resources, err = encodeValues(resources); err != nil {
+		return fmt.Errorf("ecs: encoding resources: %w", err)
+	}
+	if snapshot.Events, err = encodeValues(w.eventQueue); err != nil {
+		return fmt.Errorf("ecs: encoding events: %w", err)
+	}
+
+	if _, err := writer.Write(binarySnapshotMagic[:]); err != nil {
+		return err
+	}
+	if err := binary.Write(writer, binary.BigEndian, uint16(BinarySnapshotVersion)); err != nil {
+		return err
+	}
+	return gob.NewEncoder(writer).Encode(snapshot)
+}
+
+// LoadBinary rebuilds a world written by SaveBinary into an empty world, migrating
+// components saved at older schema versions. Components are attached through the
+// ComponentManager, so hooks fire as they load
+// The world is left partially loaded when an error is returned, and should be discarded
+func (w *World) LoadBinary(reader io.Reader) error {
+	var magic [4]byte
+	if _, err := io.ReadFull(reader, magic[:]); err != nil || magic != binarySnapshotMagic {
+		return fmt.Errorf("ecs: not a binary snapshot")
+	}
+	var version uint16
+	if err := binary.Read(reader, binary.BigEndian, &version); err != nil {
+		return fmt.Errorf("ecs: reading snapshot version: %w", err)
+	}
+	if version != BinarySnapshotVersion {
+		return fmt.Errorf("ecs: unsupported binary snapshot version %d", version)
+	}
+
+	var snapshot binarySnapshot
+	if err := gob.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return fmt.Errorf("ecs: decoding snapshot: %w", err)
+	}
+
+	err := w.restoreEntities(snapshot.Generations, snapshot.FreeIndices, snapshot.Entities)
+	if err != nil {
+		return err
+	}
+
+	for _, saved := range snapshot.Components {
+		if err := w.loadComponents(saved); err != nil {
+			return err
+		}
+	}
+
+	resources, err := decodeValues(snapshot.Resources, "resource")
+	if err != nil {
+		return err
+	}
+	for _, resource := range resources {
+		w.resources[reflect.TypeOf(resource).Elem()] = resource
+	}
+
+	events, err := decodeValues(snapshot.Events, "event")
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		w.eventQueue = append(w.eventQueue, reflect.ValueOf(event).Elem().Interface())
+	}
+
+	return nil
+}
+
+// loadComponents decodes one column of components, migrating them if they were
+// saved at an older schema version
+func (w *World) loadComponents(saved binaryComponents) error {
+	componentType, registered := lookupComponentType(saved.Type)
+	if !registered {
+		return fmt.Errorf("ecs: unknown component type %q", saved.Type)
+	}
+	path, err := migrationPath(saved.Type, saved.Version)
+	if err != nil {
+		return err
+	}
+
+	decodeType := componentType
+	if len(path) > 0 {
+		decodeType = path[0].oldType
+	}
+
+	w.ComponentManager.RegisterComponentType(saved.Type)
+	decoder := gob.NewDecoder(bytes.NewReader(saved.Data))
+	for _, entity := range saved.Entities {
+		value := reflect.New(decodeType).Interface()
+		if err := decoder.Decode(value); err != nil {
+			return fmt.Errorf("ecs: decoding %s of entity %d: %w", saved.Type, entity, err)
+		}
+		for _, migration := range path {
+			value = migration.migrate(value)
+		}
+
+		component, ok := value.(Component)
+		if !ok {
+			return fmt.Errorf("ecs: %q is not a component type", saved.Type)
+		}
+		w.ComponentManager.AddComponent(entity, saved.Type, component)
+	}
+	return nil
+}
+
+// encodeValues writes values of mixed types to a single gob stream, along with their type names
+func encodeValues(values []any) (binaryValues, error) {
+	encoded := binaryValues{}
+	var data bytes.Buffer
+	encoder := gob.NewEncoder(&data)
+	for _, value := range values {
+		t := reflect.TypeOf(value)
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		encoded.Types = append(encoded.Types, registerType(t))
+		if err := encoder.Encode(value); err != nil {
+			return binaryValues{}, fmt.Errorf("%s: %w", t, err)
+		}
+	}
+	encoded.Data = data.Bytes()
+	return encoded, nil
+}
+
+// decodeValues reads the values written by encodeValues, each as a pointer to its type
+func decodeValues(encoded binaryValues, kind string) ([]any, error) {
+	values := []any{}
+	decoder := gob.NewDecoder(bytes.NewReader(encoded.Data))
+	for _, name := range encoded.Types {
+		t, registered := lookupType(name)
+		if !registered {
+			return nil, fmt.Errorf("ecs: unknown %s type %q", kind, name)
+		}
+		value := reflect.New(t).Interface()
+		if err := decoder.Decode(value); err != nil {
+			return nil, fmt.Errorf("ecs: decoding %s %s: %w", kind, name, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
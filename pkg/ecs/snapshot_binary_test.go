@@ -0,0 +1,133 @@
+package ecs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type testEvent struct {
+	Entity Entity
+	Amount int
+}
+
+// rewriteSnapshot decodes a binary snapshot, lets edit change it, and encodes it again
+// with the same header, ie. to make one that looks like it was saved by an older version
+func rewriteSnapshot(t *testing.T, data []byte, edit func(snapshot *binarySnapshot)) []byte {
+	t.Helper()
+	header := data[:len(binarySnapshotMagic)+2]
+
+	var snapshot binarySnapshot
+	if err := gob.NewDecoder(bytes.NewReader(data[len(header):])).Decode(&snapshot); err != nil {
+		t.Fatalf("decoding snapshot: %v", err)
+	}
+	edit(&snapshot)
+
+	rewritten := bytes.NewBuffer(slices.Clone(header))
+	if err := gob.NewEncoder(rewritten).Encode(snapshot); err != nil {
+		t.Fatalf("encoding snapshot: %v", err)
+	}
+	return rewritten.Bytes()
+}
+
+// column returns the snapshot's components of the type
+func column(t *testing.T, snapshot *binarySnapshot, componentType ComponentType) *binaryComponents {
+	t.Helper()
+	for i := range snapshot.Components {
+		if snapshot.Components[i].Type == componentType {
+			return &snapshot.Components[i]
+		}
+	}
+	t.Fatalf("snapshot has no %s components", componentType)
+	return nil
+}
+
+func saveBinary(t *testing.T, w *World) []byte {
+	t.Helper()
+	var data bytes.Buffer
+	if err := w.SaveBinary(&data); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+	return data.Bytes()
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	w := newTestWorld()
+	removed := w.EntityManager.CreateEntity()
+	first := w.EntityManager.CreateEntity()
+	second := w.EntityManager.CreateEntity()
+	Add(w, first, &testPosition{X: 1, Y: 2})
+	Add(w, first, &testHealth{HP: 10})
+	Add(w, second, &testPosition{X: 3, Y: 4})
+	w.RemoveEntity(removed)
+	SetResource(w, &testCounter{Count: 7})
+	Emit(w, testEvent{Entity: first, Amount: 3})
+
+	loaded := newTestWorld()
+	if err := loaded.LoadBinary(bytes.NewReader(saveBinary(t, w))); err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+
+	want := []Entity{first, second}
+	if got := loaded.EntityManager.GetAllEntities(); !slices.Equal(got, want) {
+		t.Fatalf("entities = %v, want %v", got, want)
+	}
+	if position, _ := Get[testPosition](loaded, second); *position != (testPosition{X: 3, Y: 4}) {
+		t.Fatalf("position = %+v, want {3 4}", *position)
+	}
+	if health, _ := Get[testHealth](loaded, first); health.HP != 10 {
+		t.Fatalf("health = %+v, want 10 HP", *health)
+	}
+	if Has[testHealth](loaded, second) {
+		t.Fatal("entity has a component it wasn't saved with")
+	}
+	if counter, found := Resource[testCounter](loaded); !found || counter.Count != 7 {
+		t.Fatalf("resource = %v, want a count of 7", counter)
+	}
+
+	// The removed entity's index is reused at its next generation, as it would have been
+	if recycled := loaded.EntityManager.CreateEntity(); recycled.Index() != removed.Index() ||
+		recycled.Generation() != removed.Generation()+1 {
+		t.Fatalf("new entity = %d, want index %d at the next generation", recycled, removed.Index())
+	}
+
+	// Queued events are delivered after loading
+	delivered := []testEvent{}
+	Subscribe(loaded, func(event testEvent) {
+		delivered = append(delivered, event)
+	})
+	loaded.Update()
+	if want := []testEvent{{Entity: first, Amount: 3}}; !slices.Equal(delivered, want) {
+		t.Fatalf("events = %v, want %v", delivered, want)
+	}
+}
+
+func TestLoadBinaryChecksHeader(t *testing.T) {
+	w := newTestWorld()
+	Add(w, w.EntityManager.CreateEntity(), &testPosition{})
+	data := saveBinary(t, w)
+
+	newerFormat := slices.Clone(data)
+	newerFormat[len(binarySnapshotMagic)+1] = BinarySnapshotVersion + 1
+
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{name: "empty", data: []byte{}, err: "not a binary snapshot"},
+		{name: "json", data: []byte(`{"version": 1}`), err: "not a binary snapshot"},
+		{name: "newer format", data: newerFormat, err: "unsupported binary snapshot version 2"},
+		{name: "truncated", data: data[:len(data)/2], err: "decoding snapshot"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := newTestWorld().LoadBinary(bytes.NewReader(test.data))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("error = %v, want one containing %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ecs
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// testTarget points at another entity without being a relation
+type testTarget struct {
+	Target Entity
+}
+
+func (testTarget) IsComponent() {}
+
+func TestJSONRoundTripKeepsEntityReferences(t *testing.T) {
+	w := newTestWorld()
+
+	// Recycle the parent's index, so it is at a later generation
+	w.RemoveEntity(w.EntityManager.CreateEntity())
+	parent := w.EntityManager.CreateEntity()
+	child := w.EntityManager.CreateEntity()
+	hunter := w.EntityManager.CreateEntity()
+	if parent.Generation() == 0 {
+		t.Fatalf("parent %d wasn't recycled", parent)
+	}
+	SetParent(w, child, parent)
+	Add(w, parent, &testPosition{X: 1, Y: 2})
+	Add(w, hunter, &testTarget{Target: parent})
+
+	var data bytes.Buffer
+	if err := w.SaveJSON(&data); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+	loaded := newTestWorld()
+	if err := loaded.LoadJSON(&data); err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+
+	for _, entity := range []Entity{parent, child, hunter} {
+		if !loaded.IsAlive(entity) {
+			t.Fatalf("entity %d isn't alive at generation %d", entity, entity.Generation())
+		}
+	}
+	target, _ := Get[testTarget](loaded, hunter)
+	if position, _ := Get[testPosition](loaded, target.Target); target.Target != parent ||
+		position.X != 1 {
+		t.Fatalf("target = %d, want %d with its position", target.Target, parent)
+	}
+
+	// The relation index is rebuilt from the loaded ChildOf components
+	if got, found := Parent(loaded, child); !found || got != parent {
+		t.Fatalf("parent = %d, want %d", got, parent)
+	}
+	if got := Children(loaded, parent); !slices.Equal(got, []Entity{child}) {
+		t.Fatalf("children = %v, want %v", got, []Entity{child})
+	}
+	loaded.RemoveEntity(parent)
+	if loaded.IsAlive(child) {
+		t.Fatal("child outlived its parent after loading")
+	}
+}
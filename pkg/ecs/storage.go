@@ -0,0 +1,262 @@
+package ecs
+
+import "slices"
+
+// StorageKind selects the data structure a ComponentManager keeps components in
+type StorageKind int
+
+const (
+	// SparseSetStorage packs the components of each type into dense arrays,
+	// indexed through a sparse array of entity IDs
+	SparseSetStorage StorageKind = iota
+
+	// MapStorage keeps the components of each type in a hash map keyed by entity
+	MapStorage
+)
+
+// componentTicks records the change ticks at which a component was added and last changed
+type componentTicks struct {
+	added   uint64
+	changed uint64
+}
+
+// componentStorage holds every component of a single type
+type componentStorage interface {
+	get(entity Entity) (Component, bool)
+	set(entity Entity, component Component, tick uint64) bool
+	markChanged(entity Entity, tick uint64) bool
+	ticks(entity Entity) (componentTicks, bool)
+	remove(entity Entity) bool
+	has(entity Entity) bool
+	len() int
+	entities() []Entity
+
+	// Each type keeps its own log of removals, so systems writing different types in a
+	// parallel batch never share one
+	logRemoval(entity Entity, tick uint64)
+	removedSince(tick uint64) []Entity
+	pruneRemovals(tick uint64)
+}
+
+// componentRemoval records that a component was detached from an entity
+type componentRemoval struct {
+	entity Entity
+	tick   uint64
+}
+
+// removalLog records the components of a type that were detached, for the Removed filter
+type removalLog struct {
+	removals []componentRemoval
+}
+
+func (l *removalLog) logRemoval(entity Entity, tick uint64) {
+	l.removals = append(l.removals, componentRemoval{entity: entity, tick: tick})
+}
+
+// removedSince returns the entities that had the component detached after the tick, in
+// the order they were detached
+func (l *removalLog) removedSince(tick uint64) []Entity {
+	var entities []Entity
+	for _, removal := range l.removals {
+		if removal.tick > tick && !slices.Contains(entities, removal.entity) {
+			entities = append(entities, removal.entity)
+		}
+	}
+	return entities
+}
+
+// pruneRemovals forgets removals that happened at or before the tick
+func (l *removalLog) pruneRemovals(tick uint64) {
+	l.removals = slices.DeleteFunc(l.removals, func(r componentRemoval) bool {
+		return r.tick <= tick
+	})
+}
+
+func newComponentStorage(kind StorageKind) componentStorage {
+	switch kind {
+	case MapStorage:
+		return &mapStorage{
+			components: make(map[Entity]Component),
+			tickData:   make(map[Entity]componentTicks),
+		}
+	default:
+		return &sparseSetStorage{}
+	}
+}
+
+// sparseSetStorage stores components contiguously in dense, with sparse mapping
+// an entity index to its slot in dense (offset by one, so zero means "not present")
+// Iteration walks the dense arrays, so it is cache friendly and deterministic
+type sparseSetStorage struct {
+	removalLog
+	sparse   []int
+	dense    []Entity
+	data     []Component
+	tickData []componentTicks
+}
+
+func (s *sparseSetStorage) slot(entity Entity) (int, bool) {
+	if entity < 0 || entity.Index() >= len(s.sparse) {
+		return 0, false
+	}
+	slot := s.sparse[entity.Index()] - 1
+
+	// The slot may belong to another generation of the same index
+	return slot, slot >= 0 && s.dense[slot] == entity
+}
+
+func (s *sparseSetStorage) get(entity Entity) (Component, bool) {
+	if slot, ok := s.slot(entity); ok {
+		return s.data[slot], true
+	}
+	return nil, false
+}
+
+// set stores the component, returning false if a newer generation of the
+// entity's index holds the slot
+func (s *sparseSetStorage) set(entity Entity, component Component, tick uint64) bool {
+	if slot, ok := s.slot(entity); ok {
+		s.data[slot] = component
+		s.tickData[slot].changed = tick
+		return true
+	}
+	if entity < 0 {
+		return false
+	}
+
+	// The index is held by another generation, so only the newer of the two is kept
+	index := entity.Index()
+	if index < len(s.sparse) && s.sparse[index] != 0 {
+		occupant := s.dense[s.sparse[index]-1]
+		if occupant.Generation() > entity.Generation() {
+			return false
+		}
+		s.remove(occupant)
+	}
+
+	if index >= len(s.sparse) {
+		s.sparse = append(s.sparse, make([]int, index-len(s.sparse)+1)...)
+	}
+	s.dense = append(s.dense, entity)
+	s.data = append(s.data, component)
+	s.tickData = append(s.tickData, componentTicks{added: tick, changed: tick})
+	s.sparse[index] = len(s.dense)
+	return true
+}
+
+func (s *sparseSetStorage) markChanged(entity Entity, tick uint64) bool {
+	if slot, ok := s.slot(entity); ok {
+		s.tickData[slot].changed = tick
+		return true
+	}
+	return false
+}
+
+func (s *sparseSetStorage) ticks(entity Entity) (componentTicks, bool) {
+	if slot, ok := s.slot(entity); ok {
+		return s.tickData[slot], true
+	}
+	return componentTicks{}, false
+}
+
+func (s *sparseSetStorage) remove(entity Entity) bool {
+	slot, ok := s.slot(entity)
+	if !ok {
+		return false
+	}
+
+	// Swap the last element into the removed slot to keep dense packed
+	last := len(s.dense) - 1
+	moved := s.dense[last]
+	s.dense[slot] = moved
+	s.data[slot] = s.data[last]
+	s.tickData[slot] = s.tickData[last]
+	s.sparse[moved.Index()] = slot + 1
+
+	s.dense = s.dense[:last]
+	s.data[last] = nil
+	s.data = s.data[:last]
+	s.tickData = s.tickData[:last]
+	s.sparse[entity.Index()] = 0
+	return true
+}
+
+func (s *sparseSetStorage) has(entity Entity) bool {
+	_, ok := s.slot(entity)
+	return ok
+}
+
+func (s *sparseSetStorage) len() int {
+	return len(s.dense)
+}
+
+func (s *sparseSetStorage) entities() []Entity {
+	entities := make([]Entity, len(s.dense))
+	copy(entities, s.dense)
+	return entities
+}
+
+// mapStorage is the original hash map backend, kept for comparison
+type mapStorage struct {
+	removalLog
+	components map[Entity]Component
+	tickData   map[Entity]componentTicks
+}
+
+func (s *mapStorage) get(entity Entity) (Component, bool) {
+	component, found := s.components[entity]
+	return component, found
+}
+
+func (s *mapStorage) set(entity Entity, component Component, tick uint64) bool {
+	ticks, found := s.tickData[entity]
+	if !found {
+		ticks.added = tick
+	}
+	ticks.changed = tick
+
+	s.components[entity] = component
+	s.tickData[entity] = ticks
+	return true
+}
+
+func (s *mapStorage) markChanged(entity Entity, tick uint64) bool {
+	ticks, found := s.tickData[entity]
+	if !found {
+		return false
+	}
+	ticks.changed = tick
+	s.tickData[entity] = ticks
+	return true
+}
+
+func (s *mapStorage) ticks(entity Entity) (componentTicks, bool) {
+	ticks, found := s.tickData[entity]
+	return ticks, found
+}
+
+func (s *mapStorage) remove(entity Entity) bool {
+	if _, found := s.components[entity]; !found {
+		return false
+	}
+	delete(s.components, entity)
+	delete(s.tickData, entity)
+	return true
+}
+
+func (s *mapStorage) has(entity Entity) bool {
+	_, found := s.components[entity]
+	return found
+}
+
+func (s *mapStorage) len() int {
+	return len(s.components)
+}
+
+func (s *mapStorage) entities() []Entity {
+	entities := make([]Entity, 0, len(s.components))
+	for e := range s.components {
+		entities = append(entities, e)
+	}
+	return entities
+}
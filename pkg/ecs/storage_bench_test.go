@@ -0,0 +1,100 @@
+package ecs
+
+import (
+	"io"
+	"log"
+	"testing"
+)
+
+// Number of entities in each benchmarked world
+const benchEntities = 10000
+
+type benchPosition struct {
+	X, Y int
+}
+
+func (benchPosition) IsComponent() {}
+
+type benchVelocity struct {
+	DX, DY int
+}
+
+func (benchVelocity) IsComponent() {}
+
+var storageKinds = []struct {
+	name string
+	kind StorageKind
+}{
+	{name: "sparse-set", kind: SparseSetStorage},
+	{name: "map", kind: MapStorage},
+}
+
+// runStorages runs the benchmark once for each storage kind
+func runStorages(b *testing.B, benchmark func(b *testing.B, storageKind StorageKind)) {
+	for _, storage := range storageKinds {
+		b.Run(storage.name, func(b *testing.B) {
+			benchmark(b, storage.kind)
+		})
+	}
+}
+
+// populate creates a world with benchEntities entities that all have a position,
+// and every other entity also has a velocity
+func populate(storageKind StorageKind) (*World, []Entity) {
+	world := NewWorldWithStorage(log.New(io.Discard, "", 0), storageKind)
+	entities := make([]Entity, benchEntities)
+	for i := range entities {
+		entity := world.EntityManager.CreateEntity()
+		Add(world, entity, &benchPosition{X: i, Y: i})
+		if i%2 == 0 {
+			Add(world, entity, &benchVelocity{DX: 1, DY: 1})
+		}
+		entities[i] = entity
+	}
+	return world, entities
+}
+
+func BenchmarkAdd(b *testing.B) {
+	runStorages(b, func(b *testing.B, storageKind StorageKind) {
+		for b.Loop() {
+			populate(storageKind)
+		}
+	})
+}
+
+func BenchmarkRemove(b *testing.B) {
+	runStorages(b, func(b *testing.B, storageKind StorageKind) {
+		for b.Loop() {
+			b.StopTimer()
+			world, entities := populate(storageKind)
+			b.StartTimer()
+
+			for _, entity := range entities {
+				Remove[benchPosition](world, entity)
+			}
+		}
+	})
+}
+
+func BenchmarkGet(b *testing.B) {
+	runStorages(b, func(b *testing.B, storageKind StorageKind) {
+		world, entities := populate(storageKind)
+		for b.Loop() {
+			for _, entity := range entities {
+				Get[benchPosition](world, entity)
+			}
+		}
+	})
+}
+
+func BenchmarkQuery(b *testing.B) {
+	runStorages(b, func(b *testing.B, storageKind StorageKind) {
+		world, _ := populate(storageKind)
+		for b.Loop() {
+			for _, moving := range Query2[benchPosition, benchVelocity](world) {
+				moving.A.X += moving.B.DX
+				moving.A.Y += moving.B.DY
+			}
+		}
+	})
+}
@@ -1,77 +1,199 @@
 package ecs
 
-import "log"
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+)
 
 // World is the main ECS container that holds all entities, components, and systems
 type World struct {
 	EntityManager    *EntityManager
 	ComponentManager *ComponentManager
-	systems          []System
-	eventQueue       []Event // Simple event queue for communication
-	eventHandlers    map[EventType][]func(Event)
+	systems          []*scheduledSystem
+	schedule         [][]*scheduledSystem // Batches of systems in run order, nil when stale
+	commands         *Commands            // Structural changes deferred until the next sync point
+	eventQueue       []any                // Simple event queue for communication
+	eventHandlers    map[reflect.Type][]func(any)
+	resources        map[reflect.Type]any
+	relations        map[ComponentType]*relationIndex
+	relationTypes    []ComponentType // Registration order, so despawns are deterministic
 	Logger           *log.Logger
+
+	// lastRunTick is the change tick the running system last ran at,
+	// used by the Added, Changed and Removed filters. Zero outside of systems
+	lastRunTick uint64
+
+	// parallel is set on the views of the world systems get in a parallel batch, which
+	// share the world's maps with each other
+	parallel bool
 }
 
 func NewWorld(logger *log.Logger) *World {
-	return &World{
+	return NewWorldWithStorage(logger, SparseSetStorage)
+}
+
+// NewWorldWithStorage creates a World whose components are kept in the given kind of storage
+func NewWorldWithStorage(logger *log.Logger, storageKind StorageKind) *World {
+	world := &World{
 		EntityManager:    NewEntityManager(),
-		ComponentManager: NewComponentManager(),
-		systems:          []System{},
-		eventQueue:       []Event{},
-		eventHandlers:    make(map[EventType][]func(Event)),
+		ComponentManager: NewComponentManagerWithStorage(storageKind),
+		systems:          []*scheduledSystem{},
+		commands:         &Commands{},
+		eventQueue:       []any{},
+		eventHandlers:    make(map[reflect.Type][]func(any)),
+		resources:        make(map[reflect.Type]any),
+		relations:        make(map[ComponentType]*relationIndex),
 		Logger:           logger,
 	}
+	RegisterRelation[ChildOf](world, DespawnRecursive)
+	return world
+}
+
+// AddSystem registers a system to run during Update
+// Without options it runs in the Resolve stage, after the systems added before it
+func (w *World) AddSystem(system System, options ...SystemOption) {
+	scheduled := &scheduledSystem{
+		system: system,
+		name:   systemName(system),
+		stage:  Resolve,
+		order:  len(w.systems),
+	}
+	for _, option := range options {
+		option(scheduled)
+	}
+
+	w.systems = append(w.systems, scheduled)
+	w.schedule = nil
 }
 
-func (w *World) AddSystem(system System) {
-	w.systems = append(w.systems, system)
+// BuildSchedule orders the systems by stage and their Before / After constraints
+// It reports unknown system names and ordering cycles, and is run by Update if needed
+func (w *World) BuildSchedule() error {
+	schedule, err := buildSchedule(w.systems)
+	if err != nil {
+		return err
+	}
+	w.schedule = batchSchedule(schedule)
+	return nil
 }
 
+// IsAlive reports whether the entity exists, rejecting stale handles to removed entities
+func (w *World) IsAlive(entity Entity) bool {
+	return w.EntityManager.IsAlive(entity)
+}
+
+// RemoveEntity removes the entity and all of its components, then applies each
+// relation's DespawnPolicy to the entities related to it
 func (w *World) RemoveEntity(entity Entity) {
-	w.EntityManager.RemoveEntity(entity)
+	if !w.IsAlive(entity) {
+		return
+	}
+	targets := w.relationTargets(entity)
 	w.ComponentManager.RemoveAllComponents(entity)
+	w.EntityManager.RemoveEntity(entity)
+
+	// The entity is gone before its sources are visited, so cycles end here
+	w.despawnRelated(entity, targets)
+}
+
+// Commands returns the world's command buffer
+// Queued commands are applied after each system runs, and after events are processed
+func (w *World) Commands() *Commands {
+	return w.commands
 }
 
 func (w *World) Update() {
-	for _, system := range w.systems {
-		system.Update(w)
+	if w.schedule == nil {
+		if err := w.BuildSchedule(); err != nil {
+			panic(fmt.Sprintf("invalid system schedule: %v", err))
+		}
 	}
 
-	// Process events after all systems have updated
-	w.processEvents()
-}
+	for _, batch := range w.schedule {
+		if len(batch) == 1 {
+			w.lastRunTick = batch[0].lastRun
+			batch[0].system.Update(w)
+			w.lastRunTick = 0
+		} else {
+			w.runParallel(batch)
+		}
 
-// Simple event system for communication between ECS and external systems
-type EventType string
+		// Sync point, so the next batch sees this batch's structural changes
+		w.commands.apply(w)
 
-type Event struct {
-	Type   EventType
-	Entity Entity
-	Data   map[string]any
-}
+		// Changes from here on are newer than anything this batch has seen
+		for _, scheduled := range batch {
+			scheduled.lastRun = w.ComponentManager.changeTick
+		}
+		w.ComponentManager.changeTick++
+	}
+
+	// Process events after all systems have updated
+	w.processEvents()
+	w.commands.apply(w)
 
-func (w *World) RegisterEventHandler(eventType EventType, handler func(Event)) {
-	w.eventHandlers[eventType] = append(w.eventHandlers[eventType], handler)
+	// Every system has seen removals older than the least recent run
+	oldestRun := w.ComponentManager.changeTick
+	for _, scheduled := range w.systems {
+		oldestRun = min(oldestRun, scheduled.lastRun)
+	}
+	w.ComponentManager.pruneRemovals(oldestRun)
 }
 
-func (w *World) QueueEvent(eventType EventType, entity Entity, data map[string]any) {
-	w.eventQueue = append(w.eventQueue, Event{
-		Type:   eventType,
-		Entity: entity,
-		Data:   data,
-	})
+// ChangeTick returns the tick that component changes are currently stamped with
+func (w *World) ChangeTick() uint64 {
+	return w.ComponentManager.changeTick
 }
 
-func (w *World) processEvents() {
-	// Process all events in the queue
-	for _, event := range w.eventQueue {
-		if handlers, exists := w.eventHandlers[event.Type]; exists {
-			for _, handler := range handlers {
-				handler(event)
+// runParallel runs a batch of non-conflicting systems on their own goroutines
+// Each system gets a view of the world with its own command buffer and event queue,
+// which are merged back in schedule order so the result doesn't depend on timing
+// The component types the systems write are registered first, so adding them doesn't
+// change the shared storage map, and resources set by the systems are deferred to the
+// sync point after the batch. Anything else structural that isn't declared (ie. spawning
+// entities with other component types) has to go through Commands, and hooks run on the
+// goroutine of the system that triggered them
+func (w *World) runParallel(batch []*scheduledSystem) {
+	for _, scheduled := range batch {
+		for _, componentType := range scheduled.access.writes {
+			if isComponentType(componentType) {
+				w.ComponentManager.RegisterComponentType(componentType)
 			}
 		}
 	}
 
-	// Clear queue
-	w.eventQueue = w.eventQueue[:0]
+	views := make([]*World, len(batch))
+	var wg sync.WaitGroup
+	for i, scheduled := range batch {
+		view := *w
+		view.commands = &Commands{}
+		view.eventQueue = nil
+		view.lastRunTick = scheduled.lastRun
+		view.parallel = true
+		views[i] = &view
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scheduled.system.Update(views[i])
+		}()
+	}
+	wg.Wait()
+
+	for _, view := range views {
+		w.commands.queue = append(w.commands.queue, view.commands.queue...)
+		w.eventQueue = append(w.eventQueue, view.eventQueue...)
+	}
+}
+
+// deferInParallel makes the change to the world straight away, or at the next sync point
+// when called from a system in a parallel batch, where the world's maps are shared
+func (w *World) deferInParallel(change func(w *World)) {
+	if w.parallel {
+		w.commands.queue = append(w.commands.queue, change)
+		return
+	}
+	change(w)
 }
@@ -1,5 +1,16 @@
 package mathutils
 
+// Point is a tile of a grid, ie. the map
+type Point struct {
+	X, Y int
+}
+
+// Manhattan returns how many steps apart the points are, moving in the four cardinal
+// directions
+func Manhattan(a, b Point) int {
+	return Abs(a.X-b.X) + Abs(a.Y-b.Y)
+}
+
 func Adjacent(x1, y1, x2, y2 int) bool {
 	return Abs(x1-x2) == 1 && y1 == y2 || Abs(y1-y2) == 1 && x1 == x2
 }
@@ -0,0 +1,90 @@
+package pathfinding
+
+import (
+	"container/heap"
+
+	"ecs/pkg/mathutils"
+)
+
+// Unreachable is the distance of tiles a Dijkstra map can't reach
+const Unreachable = -1
+
+// DijkstraMap holds the cost of the cheapest path from every tile of a grid to the
+// nearest of its goals, ie. so every creature can head for the player from one map
+type DijkstraMap struct {
+	grid      Grid
+	distances []int // Row by row, Unreachable for tiles that can't reach a goal
+}
+
+// DijkstraMap builds a map of the cost from every tile to the nearest goal
+// Goals count as reachable even when they are blocked
+func (g Grid) DijkstraMap(goals ...mathutils.Point) *DijkstraMap {
+	dm := &DijkstraMap{grid: g, distances: make([]int, g.Width*g.Height)}
+	for i := range dm.distances {
+		dm.distances[i] = Unreachable
+	}
+
+	open := &queue{}
+	for _, goal := range goals {
+		if g.inBounds(goal) {
+			dm.distances[dm.index(goal)] = 0
+			heap.Push(open, &node{point: goal, order: open.pushed})
+		}
+	}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		if current.cost > dm.distances[dm.index(current.point)] {
+			continue
+		}
+
+		// Costs are walked backwards from the goals, so it costs what stepping from next
+		// onto current would
+		for _, next := range g.neighbors(current.point) {
+			if g.blocked(next) {
+				continue
+			}
+			cost := current.cost + g.cost(current.point)
+			if known := dm.distances[dm.index(next)]; known != Unreachable && cost >= known {
+				continue
+			}
+			dm.distances[dm.index(next)] = cost
+			heap.Push(open, &node{point: next, cost: cost, priority: cost, order: open.pushed})
+		}
+	}
+	return dm
+}
+
+func (dm *DijkstraMap) index(p mathutils.Point) int {
+	return p.Y*dm.grid.Width + p.X
+}
+
+// Distance returns the cost of the cheapest path from the tile to the nearest goal
+func (dm *DijkstraMap) Distance(x, y int) (int, bool) {
+	p := mathutils.Point{X: x, Y: y}
+	if !dm.grid.inBounds(p) || dm.distances[dm.index(p)] == Unreachable {
+		return Unreachable, false
+	}
+	return dm.distances[dm.index(p)], true
+}
+
+// Downhill returns the neighbour of the tile that is the cheapest step toward the nearest
+// goal, or false if the tile is a goal or can't reach one
+// Neighbours that occupied reports (ie. because another creature is standing there) are
+// skipped, in favour of the next cheapest step. Nothing is occupied when it is nil
+func (dm *DijkstraMap) Downhill(x, y int, occupied BlockerFunc) (mathutils.Point, bool) {
+	best, found := mathutils.Point{}, false
+	bestDistance, reachable := dm.Distance(x, y)
+	if !reachable {
+		return best, false
+	}
+	for _, next := range dm.grid.neighbors(mathutils.Point{X: x, Y: y}) {
+		if occupied != nil && occupied(next.X, next.Y) {
+			continue
+		}
+		if distance, reachable := dm.Distance(next.X, next.Y); reachable && distance < bestDistance {
+			best, bestDistance, found = next, distance, true
+		}
+	}
+	return best, found
+}
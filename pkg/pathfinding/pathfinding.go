@@ -0,0 +1,149 @@
+package pathfinding
+
+import (
+	"container/heap"
+
+	"ecs/pkg/mathutils"
+)
+
+// CostFunc returns what it costs to step onto the tile at x, y, which should be at least 1
+type CostFunc func(x, y int) int
+
+// BlockerFunc reports whether the tile at x, y can't be stepped onto
+type BlockerFunc func(x, y int) bool
+
+// Grid is the area paths are found across, moving in the four cardinal directions
+// Tiles off the grid are never stepped onto
+type Grid struct {
+	Width, Height int
+	Cost          CostFunc    // Every step costs 1 when nil
+	Blocked       BlockerFunc // Nothing is blocked when nil
+}
+
+// directions are the steps that can be taken from a tile, in the order they are tried
+var directions = []mathutils.Point{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}}
+
+func (g Grid) inBounds(p mathutils.Point) bool {
+	return p.X >= 0 && p.X < g.Width && p.Y >= 0 && p.Y < g.Height
+}
+
+func (g Grid) cost(p mathutils.Point) int {
+	if g.Cost == nil {
+		return 1
+	}
+	return max(g.Cost(p.X, p.Y), 1)
+}
+
+func (g Grid) blocked(p mathutils.Point) bool {
+	return g.Blocked != nil && g.Blocked(p.X, p.Y)
+}
+
+// neighbors returns the tiles next to p that are on the grid
+func (g Grid) neighbors(p mathutils.Point) []mathutils.Point {
+	neighbors := make([]mathutils.Point, 0, len(directions))
+	for _, direction := range directions {
+		next := mathutils.Point{X: p.X + direction.X, Y: p.Y + direction.Y}
+		if g.inBounds(next) {
+			neighbors = append(neighbors, next)
+		}
+	}
+	return neighbors
+}
+
+// Path finds the cheapest path between the points with A*, and returns the tiles along it
+// excluding from and including to
+// The goal can always be stepped onto, even if it is blocked, so paths can lead up to
+// another creature. Ties are broken the same way every time
+func (g Grid) Path(from, to mathutils.Point) ([]mathutils.Point, bool) {
+	if !g.inBounds(from) || !g.inBounds(to) {
+		return nil, false
+	}
+	if from == to {
+		return []mathutils.Point{}, true
+	}
+
+	costs := map[mathutils.Point]int{from: 0}
+	cameFrom := map[mathutils.Point]mathutils.Point{}
+	open := &queue{}
+	heap.Push(open, &node{point: from, priority: mathutils.Manhattan(from, to)})
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		if current.point == to {
+			return g.walkBack(cameFrom, from, to), true
+		}
+		if current.cost > costs[current.point] {
+			// A cheaper way here was found after this one was queued
+			continue
+		}
+
+		for _, next := range g.neighbors(current.point) {
+			if next != to && g.blocked(next) {
+				continue
+			}
+			cost := costs[current.point] + g.cost(next)
+			if known, seen := costs[next]; seen && cost >= known {
+				continue
+			}
+			costs[next] = cost
+			cameFrom[next] = current.point
+			heap.Push(open, &node{
+				point:    next,
+				cost:     cost,
+				priority: cost + mathutils.Manhattan(next, to),
+				order:    open.pushed,
+			})
+		}
+	}
+	return nil, false
+}
+
+func (g Grid) walkBack(
+	cameFrom map[mathutils.Point]mathutils.Point,
+	from, to mathutils.Point,
+) []mathutils.Point {
+	path := []mathutils.Point{}
+	for p := to; p != from; p = cameFrom[p] {
+		path = append(path, p)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// node is a tile waiting to be looked at by A* or a Dijkstra map
+type node struct {
+	point    mathutils.Point
+	cost     int
+	priority int
+	order    int // When the node was queued, to break ties
+}
+
+// queue is a priority queue of nodes, cheapest first
+type queue struct {
+	nodes  []*node
+	pushed int
+}
+
+func (q *queue) Len() int { return len(q.nodes) }
+
+func (q *queue) Less(i, j int) bool {
+	if q.nodes[i].priority != q.nodes[j].priority {
+		return q.nodes[i].priority < q.nodes[j].priority
+	}
+	return q.nodes[i].order < q.nodes[j].order
+}
+
+func (q *queue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *queue) Push(x any) {
+	q.nodes = append(q.nodes, x.(*node))
+	q.pushed++
+}
+
+func (q *queue) Pop() any {
+	last := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return last
+}